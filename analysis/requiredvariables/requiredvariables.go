@@ -0,0 +1,111 @@
+// Package requiredvariables defines an analyzer that reports composite
+// literals of tf2go-generated types that leave out required variables or
+// object attributes.
+//
+// Generated types are recognized by the tf2go struct tags of their fields,
+// which hold the Terraform name of each field and whether it is required:
+//
+//	LocalFilePath string `json:"local_file_path,omitempty" tf2go:"local_file_path,required"`
+//
+// Unkeyed literals are left to the compiler, which already requires every
+// field to be given, and so are literals within the generated package itself.
+package requiredvariables
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const Doc = `check that literals of tf2go-generated types set all required variables
+
+Literals of the Variables and RequiredVariables types of a tf2go-generated
+package, and of the structs generated for object types, must set every field
+whose tf2go struct tag is marked required. Otherwise Terraform fails at plan
+time because a variable or object attribute without a default is missing.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "requiredvariables",
+	Doc:      Doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.CompositeLit)(nil),
+	}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		lit := n.(*ast.CompositeLit)
+
+		named, ok := pass.TypesInfo.TypeOf(lit).(*types.Named)
+		if !ok || named.Obj().Pkg() == pass.Pkg {
+			return
+		}
+		str, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			return
+		}
+
+		required := requiredFields(str)
+		if len(required) == 0 || isUnkeyed(lit) {
+			return
+		}
+
+		for _, el := range lit.Elts {
+			kv := el.(*ast.KeyValueExpr)
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				delete(required, key.Name)
+			}
+		}
+		if len(required) == 0 {
+			return
+		}
+
+		missing := make([]string, 0, len(required))
+		for field, name := range required {
+			missing = append(missing, field+" ("+name+")")
+		}
+		sort.Strings(missing)
+
+		pass.Reportf(lit.Pos(), "%s literal is missing required %s", named.Obj().Name(), strings.Join(missing, ", "))
+	})
+
+	return nil, nil
+}
+
+// requiredFields returns the Terraform names of the fields of str that are
+// tagged as required, keyed by field name.
+func requiredFields(str *types.Struct) map[string]string {
+	required := make(map[string]string)
+	for i := 0; i < str.NumFields(); i++ {
+		tag, ok := reflect.StructTag(str.Tag(i)).Lookup("tf2go")
+		if !ok {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		for _, option := range strings.Split(options, ",") {
+			if option == "required" {
+				required[str.Field(i).Name()] = name
+			}
+		}
+	}
+	return required
+}
+
+func isUnkeyed(lit *ast.CompositeLit) bool {
+	if len(lit.Elts) == 0 {
+		return false
+	}
+	_, ok := lit.Elts[0].(*ast.KeyValueExpr)
+	return !ok
+}
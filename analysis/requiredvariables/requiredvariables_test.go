@@ -0,0 +1,12 @@
+package requiredvariables_test
+
+import (
+	"testing"
+
+	"github.com/lolabyte/tf2go/analysis/requiredvariables"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), requiredvariables.Analyzer, "example.com/user", "example.com/module")
+}
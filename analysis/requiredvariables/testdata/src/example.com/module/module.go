@@ -0,0 +1,27 @@
+package module
+
+type Server struct {
+	Name   string `json:"name,omitempty" tf2go:"name,required"`
+	Weight *int64 `json:"weight,omitempty" tf2go:"weight"`
+}
+
+type Variables struct {
+	LocalFilePath string  `json:"local_file_path,omitempty" tf2go:"local_file_path,required"`
+	Password      string  `json:"password,omitempty" tf2go:"password,required,sensitive"`
+	Server        *Server `json:"server,omitempty" tf2go:"server,required"`
+	Region        string  `json:"region,omitempty" tf2go:"region"`
+}
+
+type RequiredVariables struct {
+	LocalFilePath string  `tf2go:"local_file_path,required"`
+	Password      string  `tf2go:"password,required,sensitive"`
+	Server        *Server `tf2go:"server,required"`
+}
+
+func DefaultVariables() Variables {
+	return Variables{Region: "eu-west-1"}
+}
+
+type Untagged struct {
+	Name string `json:"name"`
+}
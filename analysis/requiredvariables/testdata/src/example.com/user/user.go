@@ -0,0 +1,24 @@
+package user
+
+import "example.com/module"
+
+func literals() {
+	_ = module.Variables{ // want `Variables literal is missing required LocalFilePath \(local_file_path\), Password \(password\), Server \(server\)`
+		Region: "us-east-1",
+	}
+
+	_ = module.Variables{
+		LocalFilePath: "/tmp",
+		Password:      "opensesame",
+		Server: &module.Server{ // want `Server literal is missing required Name \(name\)`
+			Weight: nil,
+		},
+	}
+
+	_ = module.RequiredVariables{LocalFilePath: "/tmp", Password: "opensesame", Server: &module.Server{Name: "web"}}
+	_ = module.RequiredVariables{"/tmp", "opensesame", nil}
+	_ = module.RequiredVariables{} // want `RequiredVariables literal is missing required LocalFilePath \(local_file_path\), Password \(password\), Server \(server\)`
+
+	_ = module.Untagged{}
+	_ = []module.Variables{{LocalFilePath: "/tmp", Password: "opensesame", Server: nil}}
+}
@@ -0,0 +1,14 @@
+// The tf2go-vet command checks that literals of tf2go-generated types set all
+// required variables. It can be run on its own or by go vet:
+//
+//	go vet -vettool=$(which tf2go-vet) ./...
+package main
+
+import (
+	"github.com/lolabyte/tf2go/analysis/requiredvariables"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(requiredvariables.Analyzer)
+}
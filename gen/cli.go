@@ -0,0 +1,222 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/lolabyte/tf2go/utils"
+)
+
+// cliFlags are the flags the generated command defines besides the variable
+// flags, which no variable may be named after.
+var cliFlags = []string{"auto-approve", "working-dir"}
+
+// generateCLI generates the main package of a command that runs the module,
+// with plan, apply, destroy and output subcommands and a flag for every
+// variable. importPath is the import path of the generated package.
+func generateCLI(fields []variableField, packageName, importPath string) (*j.File, error) {
+	for _, f := range fields {
+		for _, name := range cliFlags {
+			if f.variable.Name == name {
+				return nil, fmt.Errorf("variable %q: the generated command has a -%s flag of its own, rename the variable", name, name)
+			}
+		}
+	}
+
+	src := j.NewFile("main")
+	src.ImportName(importPath, packageName)
+
+	src.PackageComment(fmt.Sprintf("Command %s runs the %s Terraform module.", packageName, packageName))
+
+	usage := fmt.Sprintf(`Usage: %[1]s <command> [flags]
+
+Commands:
+  plan     show the changes applying the module would make
+  apply    create or update the infrastructure
+  destroy  destroy the infrastructure
+  output   print the outputs of the module as JSON
+
+Run %[1]s <command> -h for the flags of a command. apply and destroy show
+the plan and ask for confirmation first, unless run with -auto-approve.
+`, packageName)
+	src.Const().Id("usage").Op("=").Lit(usage).Line()
+
+	src.Func().Id("main").Params().Block(
+		j.If(j.Len(j.Qual("os", "Args")).Op("<").Lit(2)).Block(
+			j.Qual("fmt", "Fprint").Call(j.Qual("os", "Stderr"), j.Id("usage")),
+			j.Qual("os", "Exit").Call(j.Lit(2)),
+		).Line(),
+
+		j.If(
+			j.Err().Op(":=").Id("run").Call(j.Qual("context", "Background").Call(), j.Qual("os", "Args").Index(j.Lit(1)), j.Qual("os", "Args").Index(j.Lit(2).Op(":"))),
+			j.Err().Op("!=").Nil(),
+		).Block(
+			j.Qual("fmt", "Fprintln").Call(j.Qual("os", "Stderr"), j.Err()),
+			j.Qual("os", "Exit").Call(j.Lit(1)),
+		),
+	).Line()
+
+	structName := utils.SnakeToCamel(packageName)
+	src.Func().Id("run").Params(
+		j.Id("ctx").Qual("context", "Context"),
+		j.Id("command").String(),
+		j.Id("args").Index().String(),
+	).Error().Block(
+		j.Id("fs").Op(":=").Qual("flag", "NewFlagSet").Call(j.Lit(packageName+" ").Op("+").Id("command"), j.Qual("flag", "ExitOnError")),
+		j.Id("workingDir").Op(":=").Id("fs").Dot("String").Call(j.Lit("working-dir"), j.Lit("."+packageName), j.Lit("directory holding the module and its Terraform state")).Line(),
+
+		j.Var().Id("v").Op("*").Qual(importPath, "Variables"),
+		j.Var().Id("autoApprove").Bool(),
+		j.Switch(j.Id("command")).Block(
+			j.Case(j.Lit("plan")).Block(
+				j.Id("v").Op("=").Id("variableFlags").Call(j.Id("fs")),
+			),
+			j.Case(j.Lit("apply"), j.Lit("destroy")).Block(
+				j.Id("fs").Dot("BoolVar").Call(j.Op("&").Id("autoApprove"), j.Lit("auto-approve"), j.False(), j.Lit("skip the plan and the confirmation")),
+				j.Id("v").Op("=").Id("variableFlags").Call(j.Id("fs")),
+			),
+			j.Case(j.Lit("output")),
+			j.Case(j.Lit("help"), j.Lit("-h"), j.Lit("-help"), j.Lit("--help")).Block(
+				j.Qual("fmt", "Print").Call(j.Id("usage")),
+				j.Return(j.Nil()),
+			),
+			j.Default().Block(
+				j.Return(j.Qual("fmt", "Errorf").Call(j.Lit("unknown command %q\n\n%s"), j.Id("command"), j.Id("usage"))),
+			),
+		),
+		j.Id("fs").Dot("Parse").Call(j.Id("args")).Line(),
+
+		j.If(j.Id("v").Op("!=").Nil()).Block(
+			j.If(
+				j.Err().Op(":=").Qual("github.com/lolabyte/tf2go/terraform", "RequireFlags").Call(j.Id("fs"), j.Id("requiredVariables").Op("...")),
+				j.Err().Op("!=").Nil(),
+			).Block(
+				j.Return(j.Err()),
+			),
+		).Line(),
+
+		j.Id("m").Op(":=").Qual(importPath, "New"+structName).Call(j.Op("*").Id("workingDir")),
+		j.Id("m").Dot("TF").Dot("SetStdout").Call(j.Qual("os", "Stdout")),
+		j.Id("m").Dot("TF").Dot("SetStderr").Call(j.Qual("os", "Stderr")),
+		j.If(
+			j.Err().Op(":=").Id("m").Dot("Init").Call(j.Id("ctx")),
+			j.Err().Op("!=").Nil(),
+		).Block(
+			j.Return(j.Err()),
+		).Line(),
+
+		j.If(j.Id("v").Op("==").Nil()).Block(
+			j.List(j.Id("out"), j.Err()).Op(":=").Id("m").Dot("Output").Call(j.Id("ctx")),
+			j.If(j.Err().Op("!=").Nil()).Block(
+				j.Return(j.Err()),
+			),
+			j.List(j.Id("b"), j.Err()).Op(":=").Qual("encoding/json", "MarshalIndent").Call(j.Id("out"), j.Lit(""), j.Lit("  ")),
+			j.If(j.Err().Op("!=").Nil()).Block(
+				j.Return(j.Err()),
+			),
+			j.Qual("fmt", "Println").Call(j.String().Call(j.Id("b"))),
+			j.Return(j.Nil()),
+		).Line(),
+
+		j.Id("m").Dot("V").Op("=").Op("*").Id("v"),
+		j.If(
+			j.List(j.Id("_"), j.Err()).Op(":=").Id("m").Dot("V").Dot("WriteTFVarJSON").Call(j.Op("*").Id("workingDir")),
+			j.Err().Op("!=").Nil(),
+		).Block(
+			j.Return(j.Err()),
+		).Line(),
+
+		j.Switch(j.Id("command")).Block(
+			j.Case(j.Lit("plan")).Block(
+				j.List(j.Id("_"), j.Err()).Op(":=").Id("m").Dot("Plan").Call(j.Id("ctx")),
+				j.Return(j.Err()),
+			),
+			j.Case(j.Lit("apply")).Block(
+				j.If(j.Id("autoApprove")).Block(
+					j.Return(j.Id("m").Dot("Apply").Call(j.Id("ctx"))),
+				),
+			),
+			j.Default().Block(
+				j.If(j.Id("autoApprove")).Block(
+					j.Return(j.Id("m").Dot("Destroy").Call(j.Id("ctx"))),
+				),
+			),
+		),
+		j.Return(j.Id("applyPlan").Call(j.Id("ctx"), j.Id("m"), j.Id("command"))),
+	).Line()
+
+	src.Comment("applyPlan shows the plan of the apply or destroy command and applies it once the user")
+	src.Comment("confirms it.")
+	src.Func().Id("applyPlan").Params(
+		j.Id("ctx").Qual("context", "Context"),
+		j.Id("m").Op("*").Qual(importPath, structName),
+		j.Id("command").String(),
+	).Error().Block(
+		j.Const().Id("planFile").Op("=").Lit("tf2go.tfplan"),
+		j.Defer().Qual("os", "Remove").Call(j.Qual("path/filepath", "Join").Call(j.Id("m").Dot("TF").Dot("WorkingDir").Call(), j.Id("planFile"))).Line(),
+
+		j.List(j.Id("changes"), j.Err()).Op(":=").Id("m").Dot("Plan").Call(
+			j.Id("ctx"),
+			j.Qual("github.com/hashicorp/terraform-exec/tfexec", "Out").Call(j.Id("planFile")),
+			j.Qual("github.com/hashicorp/terraform-exec/tfexec", "Destroy").Call(j.Id("command").Op("==").Lit("destroy")),
+		),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Err()),
+		),
+		j.If(j.Op("!").Id("changes")).Block(
+			j.Qual("fmt", "Println").Call(j.Lit("No changes.")),
+			j.Return(j.Nil()),
+		).Line(),
+
+		j.Qual("fmt", "Printf").Call(j.Lit("\nDo you want to %s these changes? Only 'yes' will be accepted: "), j.Id("command")),
+		j.List(j.Id("answer"), j.Err()).Op(":=").Qual("bufio", "NewReader").Call(j.Qual("os", "Stdin")).Dot("ReadString").Call(j.LitRune('\n')),
+		j.If(j.Err().Op("!=").Nil().Op("&&").Err().Op("!=").Qual("io", "EOF")).Block(
+			j.Return(j.Err()),
+		),
+		j.If(j.Qual("strings", "TrimSpace").Call(j.Id("answer")).Op("!=").Lit("yes")).Block(
+			j.Return(j.Qual("fmt", "Errorf").Call(j.Lit("%s cancelled"), j.Id("command"))),
+		).Line(),
+
+		j.Return(j.Id("m").Dot("Apply").Call(j.Id("ctx"), j.Qual("github.com/hashicorp/terraform-exec/tfexec", "DirOrPlan").Call(j.Id("planFile")))),
+	).Line()
+
+	var required []j.Code
+	flags := []j.Code{j.Id("v").Op(":=").Qual(importPath, "DefaultVariables").Call()}
+	for _, f := range fields {
+		name := f.variable.Name
+		typeExpr := f.tfType.String()
+
+		usage := strings.TrimSpace(f.variable.Description)
+		if usage == "" {
+			usage = fmt.Sprintf("the %s variable", name)
+		}
+		details := []string{fmt.Sprintf("`%s`", typeExpr)}
+		if f.variable.Required {
+			details = append(details, "required")
+			required = append(required, j.Lit(name))
+		}
+		if f.variable.Sensitive {
+			details = append(details, "sensitive")
+		}
+		usage = fmt.Sprintf("%s (%s)", usage, strings.Join(details, ", "))
+
+		value := j.Qual("github.com/lolabyte/tf2go/terraform", "VariableFlag").Call(j.Lit(name), j.Lit(typeExpr), j.Op("&").Id("v").Dot(f.name))
+		if f.variable.Sensitive {
+			value = j.Qual("github.com/lolabyte/tf2go/terraform", "SensitiveFlag").Call(value)
+		}
+		flags = append(flags, j.Id("fs").Dot("Var").Call(value, j.Lit(name), j.Lit(usage)))
+	}
+	flags = append(flags, j.Return(j.Op("&").Id("v")))
+
+	src.Comment("requiredVariables names the variables without a default value, whose flags must be set.")
+	src.Var().Id("requiredVariables").Op("=").Index().String().Values(required...).Line()
+
+	src.Comment("variableFlags defines a flag for every variable on fs, and returns the variables the flags")
+	src.Comment("set, which start at their default values.")
+	src.Func().Id("variableFlags").Params(
+		j.Id("fs").Op("*").Qual("flag", "FlagSet"),
+	).Op("*").Qual(importPath, "Variables").Block(flags...)
+
+	return src, nil
+}
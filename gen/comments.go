@@ -0,0 +1,135 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/printer"
+)
+
+// docComment returns the lines of a doc comment as jennifer comments. A
+// string with a newline would be rendered as a /* */ comment, so every line
+// gets its own comment.
+func docComment(lines []string) []j.Code {
+	code := make([]j.Code, 0, len(lines))
+	for _, line := range lines {
+		code = append(code, j.Comment(line))
+	}
+	return code
+}
+
+// paragraph returns the lines of a free text description, such as the
+// description of a variable, to be added to a doc comment.
+func paragraph(s string) []string {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+	if s == "" {
+		return nil
+	}
+	return append([]string{""}, strings.Split(s, "\n")...)
+}
+
+// variableDoc returns the doc comment of the Variables field of a variable,
+// which records its description and how it was declared.
+func variableDoc(f variableField) []string {
+	v := f.variable
+	lines := []string{fmt.Sprintf("%s holds the %s variable.", f.name, v.Name)}
+	lines = append(lines, paragraph(v.Description)...)
+
+	lines = append(lines, "", "  - Terraform type: "+printer.Print(f.tfType))
+	switch {
+	case v.Required:
+		lines = append(lines, "  - Required")
+	case v.Sensitive:
+		lines = append(lines, "  - Default: (sensitive)")
+	case f.defaultValue != nil:
+		lines = append(lines, "  - Default: "+printer.Print(f.defaultValue))
+	default:
+		lines = append(lines, "  - Default: null")
+	}
+	if v.Sensitive {
+		lines = append(lines, "  - Sensitive")
+	}
+	return lines
+}
+
+// attributeDoc returns the doc comment of the field of an object struct
+// holding the attribute name of type typeExpr.
+func attributeDoc(fieldName, name string, typeExpr ast.Expression) []string {
+	lines := []string{fmt.Sprintf("%s holds the %s attribute.", fieldName, name), ""}
+
+	optional, ok := typeExpr.(*ast.OptionalTypeLiteral)
+	if !ok {
+		return append(lines, "  - Terraform type: "+printer.Print(typeExpr), "  - Required")
+	}
+	lines = append(lines, "  - Terraform type: "+printer.Print(optional.TypeExpression))
+	if optional.DefaultValue != nil {
+		return append(lines, "  - Default: "+printer.Print(optional.DefaultValue))
+	}
+	return append(lines, "  - Optional")
+}
+
+// outputDoc returns the doc comment of the Outputs field of an output.
+func outputDoc(f outputField) []string {
+	lines := []string{fmt.Sprintf("%s holds the JSON encoding of the %s output.", f.name, f.output.Name)}
+	lines = append(lines, paragraph(f.output.Description)...)
+	if f.output.Sensitive {
+		lines = append(lines, "", "  - Sensitive")
+	}
+	return lines
+}
+
+// packageDoc returns the package comment of the generated package, followed
+// by the README of the module in moduleDir when it has one.
+func packageDoc(packageName, moduleDir string) string {
+	doc := fmt.Sprintf("Package %s runs the %s Terraform module.", packageName, packageName)
+
+	for _, name := range []string{"README.md", "readme.md", "README"} {
+		b, err := os.ReadFile(filepath.Join(moduleDir, name))
+		if err != nil {
+			continue
+		}
+		if readme := markdownDoc(string(b)); readme != "" {
+			doc += "\n\n" + readme
+		}
+		break
+	}
+
+	// PackageComment renders a comment with a newline as a /* */ comment, so
+	// it is given as // lines
+	return "// " + strings.ReplaceAll(doc, "\n", "\n// ")
+}
+
+// markdownDoc converts Markdown to Go doc comment syntax. Headings of any level
+// become doc headings and fenced code blocks become indented code blocks;
+// other lines are kept, as doc comments render most of them as is.
+func markdownDoc(s string) string {
+	var lines []string
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fenced = !fenced
+			continue
+		case fenced:
+			line = "\t" + line
+		case strings.HasPrefix(trimmed, "#"):
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			// A doc heading must be a paragraph of its own
+			if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, "# "+heading, "")
+			continue
+		}
+		if line == "" && len(lines) > 0 && lines[len(lines)-1] == "" {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
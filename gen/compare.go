@@ -0,0 +1,73 @@
+package gen
+
+import (
+	j "github.com/dave/jennifer/jen"
+)
+
+// generateCompareMethods generates the DeepCopy, Equal and Diff methods of a
+// generated type, which delegate to the terraform package. Diffs name fields
+// after their tf2go tags and redact the values of sensitive ones.
+func generateCompareMethods(src *j.File, typ *j.Statement) {
+	src.Comment("DeepCopy returns a copy of v that shares no memory with it.")
+	src.Func().Params(
+		j.Id("v").Add(typ.Clone()),
+	).Id("DeepCopy").Params().Add(typ.Clone()).Block(
+		j.Return(j.Qual("github.com/lolabyte/tf2go/terraform", "DeepCopy").Call(j.Id("v"))),
+	).Line()
+
+	generateEqualAndDiff(src, typ)
+}
+
+// generateVariablesCompareMethods generates the DeepCopy, Equal and Diff
+// methods of Variables, which also copy and compare the variables set to null.
+func generateVariablesCompareMethods(src *j.File) {
+	src.Comment("DeepCopy returns a copy of v that shares no memory with it.")
+	src.Func().Params(
+		j.Id("v").Id("Variables"),
+	).Id("DeepCopy").Params().Id("Variables").Block(
+		j.Id("c").Op(":=").Qual("github.com/lolabyte/tf2go/terraform", "DeepCopy").Call(j.Id("v")),
+		j.Id("c").Dot("nulls").Op("=").Nil(),
+		j.For(j.Id("name").Op(":=").Range().Id("v").Dot("nulls")).Block(
+			j.If(j.Id("c").Dot("nulls").Op("==").Nil()).Block(
+				j.Id("c").Dot("nulls").Op("=").Make(j.Map(j.String()).Bool()),
+			),
+			j.Id("c").Dot("nulls").Index(j.Id("name")).Op("=").True(),
+		),
+		j.Return(j.Id("c")),
+	).Line()
+
+	src.Comment("NullVariables returns the Terraform names of the variables set to null with SetNull.")
+	src.Func().Params(
+		j.Id("v").Id("Variables"),
+	).Id("NullVariables").Params().Index().String().Block(
+		j.Var().Id("names").Index().String(),
+		j.For(j.Id("name").Op(":=").Range().Id("v").Dot("nulls")).Block(
+			j.Id("names").Op("=").Append(j.Id("names"), j.Id("name")),
+		),
+		j.Qual("sort", "Strings").Call(j.Id("names")),
+		j.Return(j.Id("names")),
+	).Line()
+
+	generateEqualAndDiff(src, j.Id("Variables"))
+}
+
+func generateEqualAndDiff(src *j.File, typ *j.Statement) {
+	src.Comment("Equal reports whether v and other hold the same values.")
+	src.Func().Params(
+		j.Id("v").Add(typ.Clone()),
+	).Id("Equal").Params(
+		j.Id("other").Add(typ.Clone()),
+	).Bool().Block(
+		j.Return(j.Qual("github.com/lolabyte/tf2go/terraform", "Equal").Call(j.Id("v"), j.Id("other"))),
+	).Line()
+
+	src.Comment("Diff returns the attribute paths that changed from v to other. The values of sensitive")
+	src.Comment("variables and outputs are never included.")
+	src.Func().Params(
+		j.Id("v").Add(typ.Clone()),
+	).Id("Diff").Params(
+		j.Id("other").Add(typ.Clone()),
+	).Index().Qual("github.com/lolabyte/tf2go/terraform", "Change").Block(
+		j.Return(j.Qual("github.com/lolabyte/tf2go/terraform", "Diff").Call(j.Id("v"), j.Id("other"))),
+	).Line()
+}
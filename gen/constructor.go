@@ -0,0 +1,60 @@
+package gen
+
+import (
+	"fmt"
+
+	j "github.com/dave/jennifer/jen"
+)
+
+// generateVariablesConstructor generates NewVariables, which takes the
+// variables without a default value as a RequiredVariables and the others as
+// options. RequiredVariables literals are keyed, so that values can't be given
+// to the wrong variable, and tf2go-vet reports those leaving one out.
+func generateVariablesConstructor(src *j.File, fields []variableField) {
+	var required, optional []variableField
+	for _, f := range fields {
+		if f.variable.Required {
+			required = append(required, f)
+		} else {
+			optional = append(optional, f)
+		}
+	}
+
+	requiredFields := make([]j.Code, 0, len(required))
+	assignments := make([]j.Code, 0, len(required)+1)
+	for _, f := range required {
+		requiredFields = append(requiredFields, j.Id(f.name).Add(f.typ).Tag(map[string]string{"tf2go": tf2goTag(f.variable.Name, true, f.variable.Sensitive)}))
+		assignments = append(assignments, j.Id("v").Dot(f.name).Op("=").Id("required").Dot(f.name))
+	}
+
+	src.Comment("RequiredVariables holds the variables without a default value, which must always be set.")
+	src.Comment("tf2go-vet reports literals of it that leave out a variable.")
+	src.Type().Id("RequiredVariables").Struct(requiredFields...).Line()
+
+	src.Comment("VariableOption sets a variable that has a default value.")
+	src.Type().Id("VariableOption").Func().Params(j.Op("*").Id("Variables")).Line()
+
+	for _, f := range optional {
+		src.Commentf("With%s sets the %s variable.", f.name, f.variable.Name)
+		src.Func().Id(fmt.Sprintf("With%s", f.name)).Params(
+			j.Id("value").Add(f.typ),
+		).Id("VariableOption").Block(
+			j.Return(j.Func().Params(j.Id("v").Op("*").Id("Variables")).Block(
+				j.Id("v").Dot(f.name).Op("=").Id("value"),
+			)),
+		).Line()
+	}
+
+	assignments = append(assignments, j.Line(), j.For(j.List(j.Id("_"), j.Id("opt")).Op(":=").Range().Id("opts")).Block(
+		j.Id("opt").Call(j.Op("&").Id("v")),
+	).Line())
+
+	src.Comment("NewVariables returns Variables with the required variables set, and every other variable")
+	src.Comment("set to its default value unless changed by opts.")
+	src.Func().Id("NewVariables").Params(
+		j.Id("required").Id("RequiredVariables"),
+		j.Id("opts").Op("...").Id("VariableOption"),
+	).Id("Variables").Block(
+		append(append([]j.Code{j.Id("v").Op(":=").Id("DefaultVariables").Call()}, assignments...), j.Return(j.Id("v")))...,
+	).Line()
+}
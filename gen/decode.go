@@ -0,0 +1,22 @@
+package gen
+
+import (
+	j "github.com/dave/jennifer/jen"
+)
+
+// generateDecodeVariables generates DecodeVariables, which decodes untrusted
+// JSON input into Variables strictly, unlike encoding/json.
+func generateDecodeVariables(src *j.File) {
+	src.Comment("DecodeVariables decodes variables from untrusted JSON input, such as the body of an API")
+	src.Comment("request. Unlike encoding/json, unknown variables and attributes are rejected, every value")
+	src.Comment("is checked against the type of its variable and every required variable must be set. The")
+	src.Comment("errors are terraform.DecodeErrors, which name the attribute path of each invalid value, e.g.")
+	src.Comment("container.bar.qux[2].bong. Variables set to null are set with SetNull.")
+	src.Func().Id("DecodeVariables").Params(
+		j.Id("r").Qual("io", "Reader"),
+	).Parens(j.List(j.Id("Variables"), j.Error())).Block(
+		j.Var().Id("v").Id("Variables"),
+		j.Err().Op(":=").Qual("github.com/lolabyte/tf2go/terraform", "DecodeStrict").Call(j.Id("r"), j.Op("&").Id("v")),
+		j.Return(j.Id("v"), j.Err()),
+	).Line()
+}
@@ -0,0 +1,126 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lolabyte/tf2go/terraform/printer"
+)
+
+// GenerateDocs returns a Markdown reference for the package
+// GenerateTFModulePackage generates from the module at inputModulePath with
+// the same options, with a table of the fields of Variables and of Outputs.
+// The tables are built from the same fields as the generated structs, so
+// they always match the code.
+func GenerateDocs(inputModulePath string, packageName string, opts ...Option) ([]byte, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	dir, err := os.MkdirTemp("", packageName)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	_, module, err := loadModule(context.Background(), inputModulePath, dir, o)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := variableFields(module, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", packageName)
+	fmt.Fprintf(&buf, "Package %s runs the %s Terraform module.\n", packageName, packageName)
+
+	fmt.Fprintf(&buf, "\n## Variables\n\n")
+	if len(variables) == 0 {
+		fmt.Fprintf(&buf, "The module has no variables.\n")
+	} else {
+		fmt.Fprintf(&buf, "| Go field | Terraform name | Terraform type | Default | Required | Sensitive | Description |\n")
+		fmt.Fprintf(&buf, "| --- | --- | --- | --- | --- | --- | --- |\n")
+	}
+	for _, f := range variables {
+		v := f.variable
+
+		def := ""
+		switch {
+		case v.Sensitive && f.defaultValue != nil:
+			def = "(sensitive)"
+		case f.defaultValue != nil:
+			def = codeSpan(printer.Print(f.defaultValue))
+		}
+
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			codeSpan(fmt.Sprintf("%s %#v", f.name, f.typ)),
+			codeSpan(v.Name),
+			codeSpan(printer.Print(f.tfType)),
+			def,
+			yesNo(v.Required),
+			yesNo(v.Sensitive),
+			tableCell(v.Description),
+		)
+	}
+
+	outputs := outputFields(module)
+	fmt.Fprintf(&buf, "\n## Outputs\n\n")
+	if len(outputs) == 0 {
+		fmt.Fprintf(&buf, "The module has no outputs.\n")
+	} else {
+		fmt.Fprintf(&buf, "Outputs hold the JSON encoding of their values.\n\n")
+		fmt.Fprintf(&buf, "| Go field | Terraform name | Sensitive | Description |\n")
+		fmt.Fprintf(&buf, "| --- | --- | --- | --- |\n")
+	}
+	for _, f := range outputs {
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n",
+			codeSpan(f.name+" json.RawMessage"),
+			codeSpan(f.output.Name),
+			yesNo(f.output.Sensitive),
+			tableCell(f.output.Description),
+		)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// codeSpan returns s as a Markdown code span that can be used in a table
+// cell.
+func codeSpan(s string) string {
+	// The fence must be longer than any run of backticks within s
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + escapeCell(s) + fence
+}
+
+// tableCell returns s as the text of a Markdown table cell.
+func tableCell(s string) string {
+	return escapeCell(strings.TrimSpace(s))
+}
+
+// escapeCell escapes the characters that would end a Markdown table cell or
+// row.
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
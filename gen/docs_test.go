@@ -0,0 +1,39 @@
+package gen_test
+
+import (
+	"testing"
+
+	"github.com/lolabyte/tf2go/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	t.Run("returns an error for invalid variable types", func(t *testing.T) {
+		_, err := gen.GenerateDocs("../testdata/invalid_type_tf_module", "test_module")
+		assert.Error(t, err)
+	})
+
+	t.Run("renders tables of the variables and outputs", func(t *testing.T) {
+		b, err := gen.GenerateDocs("../testdata/basic_tf_module", "test_module")
+		assert.NoError(t, err)
+
+		for _, expected := range []string{
+			"# test_module\n\nPackage test_module runs the test_module Terraform module.\n",
+			"| Go field | Terraform name | Terraform type | Default | Required | Sensitive | Description |\n",
+			"| `Environment string` | `environment` | `string` | `\"dev\"` | no | no | The environment to deploy to |\n",
+			"| `ListOfBool []*bool` | `list_of_bool` | `list(bool)` |  | yes | no |  |\n",
+			"| `ListOfNumberWithDefault []int64` | `list_of_number_with_default` | `list(number)` | `[98, 99, 100]` | no | no |  |\n",
+			"| `SensitiveString string` | `sensitive_string` | `string` |  | yes | yes |  |\n",
+			"| `Things []*Things` | `things` | `list(object({foo = list(number)}))` |  | yes | no |  |\n",
+			"| `Secret json.RawMessage` | `secret` | yes |  |\n",
+		} {
+			assert.Contains(t, string(b), expected)
+		}
+	})
+
+	t.Run("uses the Go types of the options", func(t *testing.T) {
+		b, err := gen.GenerateDocs("../testdata/basic_tf_module", "test_module", gen.WithAnyType(gen.AnyDynamic))
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), "| `Untyped terraform.Dynamic` | `untyped` | `any` |")
+	})
+}
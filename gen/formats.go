@@ -0,0 +1,51 @@
+package gen
+
+import (
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+)
+
+// generateFileHelpers generates functions to load Variables and Outputs from
+// YAML and TOML files, and methods to save them, for the formats requested
+// with WithTags.
+func generateFileHelpers(src *j.File, opts *options) {
+	for _, format := range []struct {
+		tag  TagFormat
+		name string
+	}{
+		{TagYAML, "YAML"},
+		{TagTOML, "TOML"},
+	} {
+		if !opts.hasTag(format.tag) {
+			continue
+		}
+
+		for _, typ := range []struct {
+			name, recv string
+		}{
+			{"Variables", "v"},
+			{"Outputs", "o"},
+		} {
+			load := "Load" + typ.name + format.name
+			src.Commentf("%s reads %s from a %s file, keyed by their Terraform names.", load, typ.name, format.name)
+			src.Func().Id(load).Params(
+				j.Id("path").String(),
+			).Parens(j.List(j.Id(typ.name), j.Error())).Block(
+				j.Var().Id(typ.recv).Id(typ.name),
+				j.Err().Op(":=").Qual("github.com/lolabyte/tf2go/terraform", "Load"+format.name).Call(j.Id("path"), j.Op("&").Id(typ.recv)),
+				j.Return(j.Id(typ.recv), j.Err()),
+			).Line()
+
+			save := "Save" + format.name
+			src.Commentf("%s writes the %s to a %s file, keyed by their Terraform names.", save, strings.ToLower(typ.name), format.name)
+			src.Func().Params(
+				j.Id(typ.recv).Id(typ.name),
+			).Id(save).Params(
+				j.Id("path").String(),
+			).Error().Block(
+				j.Return(j.Qual("github.com/lolabyte/tf2go/terraform", "Save"+format.name).Call(j.Id("path"), j.Id(typ.recv))),
+			).Line()
+		}
+	}
+}
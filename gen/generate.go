@@ -1,45 +1,76 @@
 package gen
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
-	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 
 	j "github.com/dave/jennifer/jen"
 	"github.com/hashicorp/go-getter"
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/lolabyte/tf2go/terraform"
 	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/checker"
 	tfLexer "github.com/lolabyte/tf2go/terraform/lexer"
 	tfParser "github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/lolabyte/tf2go/terraform/printer"
 	"github.com/lolabyte/tf2go/utils"
-	cp "github.com/otiai10/copy"
 )
 
-func GenerateTFModulePackage(inputModulePath string, outPackageDir string, packageName string, embedDir string) error {
-	dir, err := os.MkdirTemp("", packageName)
-	if err != nil {
-		return err
+// GenerateTFModulePackage generates a Go package named packageName for the
+// Terraform module at inputModulePath into outPackageDir, embedding the
+// module from embedDir under it. Warnings are printed to stderr; use Generate
+// to get them and the generated files in memory instead.
+func GenerateTFModulePackage(inputModulePath string, outPackageDir string, packageName string, embedDir string, opts ...Option) error {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
 	}
 
-	moduleDir, err := getInputModule(inputModulePath, dir)
+	result, err := Generate(context.Background(), Options{
+		Module:         inputModulePath,
+		PackageName:    packageName,
+		EmbedDir:       embedDir,
+		AnyType:        o.anyType,
+		Tags:           o.tags,
+		ProtoPackage:   o.protoPackage,
+		ProtoGoPackage: o.protoGoPackage,
+		CLIImportPath:  o.cliImportPath,
+	})
 	if err != nil {
-		return fmt.Errorf("unable to get module from path %s: %v", dir, err)
+		return err
 	}
-	defer os.RemoveAll(dir)
 
-	module, diags := tfconfig.LoadModule(moduleDir)
-	if diags.HasErrors() {
-		return diags.Err()
+	for _, d := range result.Diagnostics {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", d)
 	}
 
+	return result.Write(outPackageDir)
+}
+
+// generatePackage generates the source of the package for the module loaded
+// from moduleDir and returns it along with the fields of its Variables.
+func generatePackage(moduleDir string, module *tfconfig.Module, packageName string, embedDir string, o *options) (*j.File, []variableField, error) {
 	out := j.NewFile(packageName)
+	out.PackageComment(packageDoc(packageName, moduleDir))
 
 	out.Commentf("//go:embed %s", path.Join(embedDir, "*"))
 	out.Var().Id("tfModule").Qual("embed", "FS")
 
-	generateVarStructs(out, module)
+	fields, err := generateVarStructs(out, module, o)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nullable, err := loadNullable(moduleDir, module)
+	if err != nil {
+		return nil, nil, err
+	}
+	generateNullHandling(out, nullable, fields, o)
 
 	out.Func().Params(
 		j.Id("v").Id("Variables"),
@@ -64,7 +95,10 @@ func GenerateTFModulePackage(inputModulePath string, outPackageDir string, packa
 		j.Return(j.Id("outfile"), j.Nil()),
 	).Line()
 
-	generateOutputStruct(out, module)
+	if err := generateOutputStruct(out, module, o); err != nil {
+		return nil, nil, err
+	}
+	generateFileHelpers(out, o)
 
 	out.Func().Params(
 		j.Id("o").Id("Outputs"),
@@ -243,29 +277,49 @@ func GenerateTFModulePackage(inputModulePath string, outPackageDir string, packa
 		j.Return(j.Id("m").Dot("TF").Dot("Import").Call(j.Id("ctx"), j.Id("address"), j.Id("id"), j.Id("opts").Op("..."))),
 	).Line()
 
-	// Copy the Terraform module to the go:embed path
-	err = os.MkdirAll(path.Join(outPackageDir, embedDir), os.ModePerm)
+	return out, fields, nil
+}
+
+// loadModule loads the configuration of the module at inputModulePath,
+// which is first downloaded into dir when it isn't a local path, and returns
+// the directory holding it. Warnings about the configuration are reported
+// through o.
+func loadModule(ctx context.Context, inputModulePath string, dir string, o *options) (string, *tfconfig.Module, error) {
+	moduleDir, err := getInputModule(ctx, inputModulePath, dir)
 	if err != nil {
-		return fmt.Errorf("failed to create output dir: %v", err)
+		return "", nil, fmt.Errorf("unable to get module from path %s: %v", dir, err)
 	}
 
-	err = out.Save(path.Join(outPackageDir, fmt.Sprintf("%s.go", packageName)))
-	if err != nil {
-		return fmt.Errorf("failed to save module to file: %v", err)
+	module, diags := tfconfig.LoadModule(moduleDir)
+	if diags.HasErrors() {
+		return "", nil, diags.Err()
+	}
+	for _, d := range diags {
+		o.warning("%s: %s", d.Summary, d.Detail)
 	}
 
-	copyDirectory(moduleDir, path.Join(outPackageDir, embedDir))
+	o.quotedTypes, err = loadQuotedTypes(moduleDir)
+	if err != nil {
+		return "", nil, err
+	}
 
-	return nil
+	return moduleDir, module, nil
 }
 
-func getInputModule(src, dst string) (string, error) {
+func getInputModule(ctx context.Context, src, dst string) (string, error) {
 	_, err := os.Stat(src)
 	if !os.IsNotExist(err) {
 		return src, nil
 	}
 
+	// Local paths that don't exist are left for tfconfig to report
+	detected, err := getter.Detect(src, dst, getter.Detectors)
+	if err == nil && strings.HasPrefix(detected, "file://") {
+		return src, nil
+	}
+
 	client := getter.Client{
+		Ctx:  ctx,
 		Src:  src,
 		Dst:  dst,
 		Pwd:  dst,
@@ -274,109 +328,192 @@ func getInputModule(src, dst string) (string, error) {
 	return dst, client.Get()
 }
 
-func copyDirectory(srcDir string, destDir string) {
-	src, err := filepath.Abs(srcDir)
-	if err != nil {
-		panic(err)
-	}
-
-	dest, err := filepath.Abs(destDir)
-	if err != nil {
-		panic(err)
-	}
-
-	opts := cp.Options{
-		Skip: func(f os.FileInfo, src, dest string) (bool, error) {
-			if f.IsDir() && f.Name() == ".terraform" {
-				return true, nil
-			}
-			ok, err := path.Match("*/.teraform/*", src)
-			if err != nil {
-				return false, err
-			}
-			if ok {
-				return true, nil
-			}
-			return false, nil
-		},
-	}
-	err = cp.Copy(src, dest, opts)
-	if err != nil {
-		panic(err)
-	}
-}
-
 type kvpair struct {
 	name  string
 	value ast.Expression
 }
 
-func eval(src *j.File, node ast.Node, stmt *j.Statement, name string) *j.Statement {
+func eval(src *j.File, opts *options, node ast.Node, stmt *j.Statement, name string) *j.Statement {
 	switch node := node.(type) {
 	case *ast.Type:
 		for _, s := range node.Statements {
-			return eval(src, s.(*ast.ExpressionStatement).Expression, stmt, name)
+			return eval(src, opts, s.(*ast.ExpressionStatement).Expression, stmt, name)
 		}
 	case *ast.AnyTypeLiteral:
+		if opts.anyType == AnyDynamic {
+			return stmt.Qual("github.com/lolabyte/tf2go/terraform", "Dynamic")
+		}
 		return stmt.Interface()
 	case *ast.BoolTypeLiteral:
 		return stmt.Op("*").Bool()
 	case *ast.NumberTypeLiteral:
+		if opts.isFloat(node) {
+			return stmt.Float64()
+		}
 		return stmt.Int64()
 	case *ast.StringTypeLiteral:
 		return stmt.String()
 	case *ast.ListTypeLiteral:
-		return eval(src, node.TypeExpression, stmt.Index(), name)
+		return eval(src, opts, node.TypeExpression, stmt.Index(), name)
 	case *ast.MapTypeLiteral:
-		return eval(src, node.TypeExpression, stmt.Map(j.String()), name)
+		return eval(src, opts, node.TypeExpression, stmt.Map(j.String()), name)
 	case *ast.ObjectTypeLiteral:
-		var fields []j.Code
+		fields := objectFields(src, opts, node)
 
-		var kvpairs []kvpair
-		for k, v := range node.ObjectSpec.(*ast.ObjectLiteral).KVPairs {
-			kvpairs = append(kvpairs, kvpair{k.String(), v})
-		}
-		sort.Slice(kvpairs, func(i, j int) bool { return kvpairs[i].name < kvpairs[j].name })
+		structName := utils.SnakeToCamel(name)
+		if src != nil && !opts.structs[structName] {
+			if opts.structs == nil {
+				opts.structs = make(map[string]bool)
+			}
+			opts.structs[structName] = true
 
-		for _, kv := range kvpairs {
-			structName := utils.SnakeToCamel(kv.name)
-			tag := structTagsForField(kv.name)
-			field := j.Id(structName)
-			fields = append(fields, eval(src, kv.value, field, kv.name).Tag(tag))
+			src.Commentf("%s holds a value of the Terraform type %s.", structName, printer.Print(node))
+			src.Type().Id(structName).Struct(fields...).Line()
+			generateCompareMethods(src, j.Op("*").Id(structName))
 		}
-
-		structName := utils.SnakeToCamel(name)
-		src.Type().Id(structName).Struct(fields...).Line()
 		return stmt.Op("*").Id(structName)
 	case *ast.OptionalTypeLiteral:
-		switch node.TypeExpression.(type) {
-		case *ast.ObjectLiteral:
-			return eval(src, node.TypeExpression, stmt, name)
-		case *ast.MapTypeLiteral:
-			return eval(src, node.TypeExpression, stmt, name)
-		case *ast.ListTypeLiteral:
-			return eval(src, node.TypeExpression, stmt, name)
-		default:
-			return eval(src, node.TypeExpression, stmt.Op("*"), name)
+		if isNilableType(node.TypeExpression) {
+			return eval(src, opts, node.TypeExpression, stmt, name)
 		}
+		return eval(src, opts, node.TypeExpression, stmt.Op("*"), name)
 	}
 	return nil
 }
 
-func astNodeType(v *tfconfig.Variable) ast.Node {
-	lexer := tfLexer.New(v.Type)
+// objectFields returns the fields of the struct generated for the object type
+// node, generating the structs of its attributes into src unless it is nil.
+func objectFields(src *j.File, opts *options, node *ast.ObjectTypeLiteral) []j.Code {
+	var fields []j.Code
+
+	var kvpairs []kvpair
+	for k, v := range node.ObjectSpec.(*ast.ObjectLiteral).KVPairs {
+		kvpairs = append(kvpairs, kvpair{k.String(), v})
+	}
+	sort.Slice(kvpairs, func(i, j int) bool { return kvpairs[i].name < kvpairs[j].name })
+
+	for i, kv := range kvpairs {
+		structName := utils.SnakeToCamel(kv.name)
+		_, optional := kv.value.(*ast.OptionalTypeLiteral)
+		tag := variableTagsForField(opts, kv.name, !optional, false)
+		field := j.Id(structName)
+		if i > 0 {
+			fields = append(fields, j.Line())
+		}
+		fields = append(fields, docComment(attributeDoc(structName, kv.name, kv.value))...)
+		fields = append(fields, eval(src, opts, kv.value, field, kv.name).Tag(tag))
+	}
+	return fields
+}
+
+// astNodeType returns the type of a variable, reporting the warnings of the
+// parser through o, and records the number types with a fractional default
+// value in o.
+func astNodeType(v *tfconfig.Variable, o *options) (ast.Node, error) {
+	t, err := parseNodeType(v, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var value ast.Expression
+	if !v.Required && v.Default != nil {
+		// An invalid default is reported along with the Go value of the
+		// default
+		value, _ = astNodeDefault(v)
+	}
+	o.markFractionalNumbers(t, value)
+
+	return t, nil
+}
+
+func parseNodeType(v *tfconfig.Variable, o *options) (ast.Node, error) {
+	if v.Type == "" {
+		return inferredNodeType(v)
+	}
+
+	// The parser warns about quoted types when it is given the quotes
+	src := v.Type
+	if o != nil && o.quotedTypes[v.Name] {
+		src = strconv.Quote(src)
+	}
+
+	lexer := tfLexer.New(src)
 	parser := tfParser.New(lexer)
 
-	return parser.ParseType()
+	t := parser.ParseType()
+	for _, w := range parser.Warnings() {
+		o.warning("variable %q: %s", v.Name, w)
+	}
+
+	// The parser recovers from errors, so the checker can report problems in
+	// the rest of the type at the same time
+	errs := append(parser.Errors(), checker.Check(t)...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid type for variable %q: %s", v.Name, strings.Join(errs, "; "))
+	}
+
+	return t, nil
 }
 
-func structTagsForField(name string) map[string]string {
+// inferredNodeType returns the type of a variable declared without one, which
+// is inferred from its default value. Without a default, any value is
+// accepted.
+func inferredNodeType(v *tfconfig.Variable) (ast.Node, error) {
+	if v.Required || v.Default == nil {
+		return terraform.InferType(&ast.NullLiteral{}), nil
+	}
+
+	value, err := astNodeDefault(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return terraform.InferType(value), nil
+}
+
+// structTagsForField returns the encoding struct tags for a field, which
+// leave the field out of the encoding when it is empty if omitempty is set.
+// YAML and TOML get no tags, as their file helpers go through JSON.
+func structTagsForField(opts *options, name string, omitempty bool) map[string]string {
+	value := name
+	if omitempty {
+		value += ",omitempty"
+	}
 	tags := map[string]string{
-		"json": fmt.Sprintf("%s,omitempty", name),
+		"json": value,
+	}
+	for _, format := range opts.tags {
+		if format != TagYAML && format != TagTOML {
+			tags[string(format)] = value
+		}
 	}
 	return tags
 }
 
+// variableTagsForField returns the struct tags for a field holding a variable
+// or object attribute. The tf2go tag records its Terraform name and whether it
+// is required or sensitive, which lets tools such as the requiredvariables analyzer
+// recognize generated types. Required fields are always encoded, as their zero
+// values, such as 0 or false, are values Terraform must be given.
+func variableTagsForField(opts *options, name string, required, sensitive bool) map[string]string {
+	tags := structTagsForField(opts, name, !required)
+	tags["tf2go"] = tf2goTag(name, required, sensitive)
+	return tags
+}
+
+// tf2goTag returns the tf2go struct tag of a field, which is also read by the
+// terraform package to name the field in diffs and redact sensitive values.
+func tf2goTag(name string, required, sensitive bool) string {
+	tag := name
+	if required {
+		tag += ",required"
+	}
+	if sensitive {
+		tag += ",sensitive"
+	}
+	return tag
+}
+
 func structFieldNameForVar(v *tfconfig.Variable) string {
 	return utils.SnakeToCamel(v.Name)
 }
@@ -385,49 +522,71 @@ func structFieldNameForOutput(v *tfconfig.Output) string {
 	return utils.SnakeToCamel(v.Name)
 }
 
-func generateVarStructs(src *j.File, mod *tfconfig.Module) {
-	var defaultVarStructFields []j.Code
-
-	// Sort alphabetically
-	var variables []*tfconfig.Variable
-	for _, v := range mod.Variables {
-		variables = append(variables, v)
+func generateVarStructs(src *j.File, mod *tfconfig.Module, opts *options) ([]variableField, error) {
+	fields, err := variableFields(mod, opts)
+	if err != nil {
+		return nil, err
 	}
-	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
 
-	for _, v := range variables {
-		if v.Type == "" {
-			v.Type = "string"
+	var defaultVarStructFields []j.Code
+	defaults := j.Dict{}
+	for i, f := range fields {
+		v := f.variable
+		tag := variableTagsForField(opts, v.Name, v.Required, v.Sensitive)
+		field := eval(src, opts, f.tfType, j.Id(f.name), v.Name).Tag(tag)
+		if i > 0 {
+			defaultVarStructFields = append(defaultVarStructFields, j.Line())
 		}
+		defaultVarStructFields = append(defaultVarStructFields, docComment(variableDoc(f))...)
+		defaultVarStructFields = append(defaultVarStructFields, field)
 
-		fieldName := structFieldNameForVar(v)
-		tag := structTagsForField(v.Name)
-		field := eval(src, astNodeType(v), j.Id(fieldName), v.Name).Tag(tag)
-		if v.Description != "" {
-			field = field.Comment(v.Description)
+		if f.defaultCode != nil {
+			defaults[j.Id(f.name)] = f.defaultCode
 		}
-
-		defaultVarStructFields = append(defaultVarStructFields, field)
 	}
 
+	defaultVarStructFields = append(defaultVarStructFields,
+		j.Line().Comment("nulls holds the names of the variables set to null with SetNull"),
+		j.Id("nulls").Map(j.String()).Bool(),
+	)
+	src.Comment("Variables holds the input variables of the module.")
 	src.Type().Id("Variables").Struct(defaultVarStructFields...).Line()
-}
 
-func generateOutputStruct(src *j.File, mod *tfconfig.Module) {
-	var outputStructFields []j.Code
+	src.Comment("DefaultVariables returns Variables set to the default value of every variable that has one.")
+	src.Func().Id("DefaultVariables").Params().Id("Variables").Block(
+		j.Return(j.Id("Variables").Values(defaults)),
+	).Line()
 
-	// Sort alphabetically
-	var outputs []*tfconfig.Output
-	for _, v := range mod.Outputs {
-		outputs = append(outputs, v)
-	}
-	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+	src.Func().Id("ptr").Types(j.Id("T").Any()).Params(j.Id("v").Id("T")).Op("*").Id("T").Block(
+		j.Return(j.Op("&").Id("v")),
+	).Line()
+
+	generateVariablesConstructor(src, fields)
+	generateVariablesCompareMethods(src)
+	generateTFVars(src, fields)
+	generateDecodeVariables(src)
 
-	for _, v := range outputs {
-		fieldName := structFieldNameForOutput(v)
-		tag := structTagsForField(v.Name)
-		field := j.Id(fieldName).Qual("encoding/json", "RawMessage").Tag(tag)
+	return fields, nil
+}
+
+func generateOutputStruct(src *j.File, mod *tfconfig.Module, opts *options) error {
+	var outputStructFields []j.Code
+	for i, f := range outputFields(mod) {
+		if err := checkMethodCollision("Outputs", f.name, opts); err != nil {
+			return fmt.Errorf("output %q: %v", f.output.Name, err)
+		}
+		tag := structTagsForField(opts, f.output.Name, true)
+		tag["tf2go"] = tf2goTag(f.output.Name, false, f.output.Sensitive)
+		field := j.Id(f.name).Qual("encoding/json", "RawMessage").Tag(tag)
+		if i > 0 {
+			outputStructFields = append(outputStructFields, j.Line())
+		}
+		outputStructFields = append(outputStructFields, docComment(outputDoc(f))...)
 		outputStructFields = append(outputStructFields, field)
 	}
+	src.Comment("Outputs holds the outputs of the module.")
 	src.Type().Id("Outputs").Struct(outputStructFields...).Line()
+
+	generateCompareMethods(src, j.Id("Outputs"))
+	return nil
 }
@@ -1,6 +1,12 @@
 package gen_test
 
 import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/lolabyte/tf2go/gen"
@@ -19,4 +25,441 @@ func TestGenerateTFModulePackage(t *testing.T) {
 		assert.Error(t, err)
 		assert.Regexp(t, "Argument or block definition required:.*$", err.Error())
 	})
+
+	t.Run("returns an error for every variable with an invalid type", func(t *testing.T) {
+		err := gen.GenerateTFModulePackage("../testdata/invalid_type_tf_module", "out_dir", "test_module", "tf")
+		assert.Error(t, err)
+		assert.Equal(t, strings.Join([]string{
+			`invalid type for variable "broken": 2:12: unexpected "("; a: unknown type keyword "foo"; b: list() requires exactly 1 argument, got 2`,
+			`invalid type for variable "optional_list": optional() may only be used for the attributes of an object type`,
+		}, "\n"), err.Error())
+	})
+
+	t.Run("warns about legacy type syntax", func(t *testing.T) {
+		moduleDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`
+variable "quoted" {
+  type = "string"
+}
+
+variable "quoted_map" {
+  type = "map"
+}
+
+variable "bare_list" {
+  type = list
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		stderr := captureStderr(t, func() {
+			err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf")
+		})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			`warning: variable "quoted": quoted type constraints are deprecated, use string instead of "string"`,
+			`warning: variable "quoted_map": quoted type constraints are deprecated, use map(any) instead of "map"`,
+			`warning: variable "bare_list": bare list type is deprecated, use list(any) instead`,
+		}, strings.Split(strings.TrimSpace(stderr), "\n"))
+	})
+
+	t.Run("generates composite literals for default values and infers missing types", func(t *testing.T) {
+		src := generateBasicModule(t)
+		for _, expected := range []string{
+			`ListOfBoolWithDefault: []*bool{ptr(false), ptr(true), ptr(false)},`,
+			`Weight: ptr[int64](-1),`,
+			"UntypedSettings *UntypedSettings `json:\"untyped_settings,omitempty\"",
+		} {
+			assert.Contains(t, src, expected)
+		}
+	})
+
+	t.Run("generates null handling from the nullable argument", func(t *testing.T) {
+		src := generateBasicModule(t)
+		assert.Contains(t, src, `"region": false,`)
+		assert.Contains(t, src, `func (v *Variables) SetNull(name string) error {`)
+	})
+
+	t.Run("generates a constructor taking the required variables", func(t *testing.T) {
+		src := generateBasicModule(t)
+		assert.Contains(t, src, "func NewVariables(required RequiredVariables, opts ...VariableOption) Variables {")
+		assert.NotContains(t, src, "func NewRequiredVariables(")
+		assert.NotContains(t, src, "func WithLocalFilePath(")
+	})
+
+	t.Run("tags required variables and attributes for the requiredvariables analyzer", func(t *testing.T) {
+		src := generateBasicModule(t)
+		for _, expected := range []string{
+			"LocalFilePath string `json:\"local_file_path\" tf2go:\"local_file_path,required\"`",
+			"Weight *int64 `json:\"weight,omitempty\" tf2go:\"weight\"`",
+			"LocalFilePath string `tf2go:\"local_file_path,required\"`",
+		} {
+			assert.Contains(t, src, expected)
+		}
+	})
+
+	t.Run("generates DeepCopy, Equal and Diff methods aware of sensitive values", func(t *testing.T) {
+		src := generateBasicModule(t)
+		for _, expected := range []string{
+			"func (v Variables) Diff(other Variables) []terraform.Change {",
+			"func (v Outputs) DeepCopy() Outputs {",
+		} {
+			assert.Contains(t, src, expected)
+		}
+	})
+
+	t.Run("generates tfvars loading and writing checked against the variable types", func(t *testing.T) {
+		src := generateBasicModule(t)
+		for _, expected := range []string{
+			`"untyped_ports": "list(number)",`,
+			"func (v Variables) WriteTFVars(workingDir string) (string, error) {",
+		} {
+			assert.Contains(t, src, expected)
+		}
+	})
+
+	t.Run("generates strict decoding of untrusted input", func(t *testing.T) {
+		src := generateBasicModule(t)
+		assert.Contains(t, src, "func DecodeVariables(r io.Reader) (Variables, error) {")
+		assert.Contains(t, src, "err := terraform.DecodeStrict(r, &v)")
+	})
+
+	t.Run("generates mapstructure tags and file helpers", func(t *testing.T) {
+		src := generateBasicModule(t, gen.WithTags(gen.TagYAML, gen.TagTOML, gen.TagMapstructure))
+		for _, expected := range []string{
+			"Region string `json:\"region,omitempty\" mapstructure:\"region,omitempty\" tf2go:\"region\"`",
+			"Name string `json:\"name\" mapstructure:\"name\" tf2go:\"name,required\"`",
+			"func (v Variables) SaveYAML(path string) error {",
+			"func LoadOutputsTOML(path string) (Outputs, error) {",
+		} {
+			assert.Contains(t, src, expected)
+		}
+
+		src = generateBasicModule(t, gen.WithTags(gen.TagMapstructure))
+		assert.NotContains(t, src, "SaveYAML")
+	})
+
+	t.Run("returns an error for fields named after generated methods", func(t *testing.T) {
+		moduleDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`
+variable "diff" {
+  type = string
+}
+
+variable "server" {
+  type = object({ equal = bool, save_yaml = string })
+}
+
+output "deep_copy" {
+  value = var.diff
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf", gen.WithTags(gen.TagYAML))
+		assert.EqualError(t, err, strings.Join([]string{
+			`variable "diff": field Diff has the name of the generated method Variables.Diff, rename it`,
+			`variable "server": attribute "equal": field Equal has the name of the generated method Server.Equal, rename it`,
+		}, "\n"))
+
+		err = os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`
+output "deep_copy" {
+  value = "x"
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf")
+		assert.EqualError(t, err, `output "deep_copy": field DeepCopy has the name of the generated method Outputs.DeepCopy, rename it`)
+	})
+
+	t.Run("shares the struct of object types of the same shape", func(t *testing.T) {
+		moduleDir, outDir := t.TempDir(), t.TempDir()
+		err := os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`
+variable "a" {
+  type = object({ config = object({ x = string }) })
+}
+
+variable "b" {
+  type = list(object({ config = object({ x = string }) }))
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, outDir, "test_module", "tf", gen.WithProto("test.v1", "example.com/test_module/testpb"))
+		assert.NoError(t, err)
+
+		src, err := os.ReadFile(filepath.Join(outDir, "test_module.go"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(src), "type Config struct {"))
+
+		protoSrc, err := os.ReadFile(filepath.Join(outDir, "test_module.proto"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(protoSrc), "message Config {"))
+
+		err = os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`
+variable "a" {
+  type = object({ config = object({ x = number }) })
+}
+
+variable "b" {
+  type    = object({ config = object({ x = number }) })
+  default = { config = { x = 0.5 } }
+}
+
+variable "c" {
+  type = object({ config = object({ y = string }) })
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf")
+		assert.EqualError(t, err, strings.Join([]string{
+			`variable "b": struct Config of the type object({x = number}) differs from the struct Config of variable "a", of the type object({x = number}), rename one of them`,
+			`variable "c": struct Config of the type object({y = string}) differs from the struct Config of variable "a", of the type object({x = number}), rename one of them`,
+		}, "\n"))
+	})
+
+	t.Run("returns an error for unsupported struct tags", func(t *testing.T) {
+		err := gen.GenerateTFModulePackage("../testdata/basic_tf_module", t.TempDir(), "test_module", "tf", gen.WithTags("xml"))
+		assert.EqualError(t, err, `unsupported struct tag "xml"`)
+	})
+
+	t.Run("generates protobuf messages and conversions", func(t *testing.T) {
+		outDir := t.TempDir()
+		err := gen.GenerateTFModulePackage("../testdata/basic_tf_module", outDir, "test_module", "tf", gen.WithProto("test.v1", "example.com/test_module/testpb"))
+		assert.NoError(t, err)
+
+		protoSrc, err := os.ReadFile(filepath.Join(outDir, "test_module.proto"))
+		assert.NoError(t, err)
+		for _, expected := range []string{
+			"package test.v1;",
+			`option go_package = "example.com/test_module/testpb";`,
+			"  // The environment to deploy to\n  string environment = 535702524;",
+			"message OptionalList {\n  repeated int64 values = 340216893;\n}",
+		} {
+			assert.Contains(t, string(protoSrc), expected)
+		}
+
+		goSrc, err := os.ReadFile(filepath.Join(outDir, "test_module_proto.go"))
+		assert.NoError(t, err)
+		for _, expected := range []string{
+			"func (v Variables) ToProto() (*testpb.Variables, error) {",
+			"func ServerFromProto(m *testpb.Server) (*Server, error) {",
+		} {
+			assert.Contains(t, string(goSrc), expected)
+		}
+
+		err = gen.GenerateTFModulePackage("../testdata/basic_tf_module", outDir, "test_module", "tf", gen.WithProto("test-v1", "example.com/test_module/testpb"))
+		assert.EqualError(t, err, `invalid protobuf package "test-v1"`)
+	})
+
+	t.Run("numbers protobuf fields independently of the other fields", func(t *testing.T) {
+		generate := func(variables string) string {
+			moduleDir, outDir := t.TempDir(), t.TempDir()
+			err := os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(variables), 0o644)
+			assert.NoError(t, err)
+
+			err = gen.GenerateTFModulePackage(moduleDir, outDir, "test_module", "tf", gen.WithProto("test.v1", "example.com/test_module/testpb"))
+			assert.NoError(t, err)
+
+			protoSrc, err := os.ReadFile(filepath.Join(outDir, "test_module.proto"))
+			assert.NoError(t, err)
+			return string(protoSrc)
+		}
+
+		before := generate(`variable "region" { type = string }`)
+		after := generate(`
+variable "name" { type = string }
+variable "region" { type = string }
+`)
+		number := regexp.MustCompile(`string region = \d+;`)
+		assert.Equal(t, number.FindString(before), number.FindString(after))
+		assert.NotEmpty(t, number.FindString(before))
+	})
+
+	t.Run("returns an error for fields with the same protobuf number", func(t *testing.T) {
+		moduleDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`
+variable "nqbi" { type = string }
+variable "rpaa" { type = string }
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf", gen.WithProto("test.v1", "example.com/test_module/testpb"))
+		assert.EqualError(t, err, "message Variables: fields nqbi and rpaa both have the number 189946820, rename one of them")
+	})
+
+	t.Run("returns an error for names colliding with the protobuf conversions", func(t *testing.T) {
+		moduleDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`
+variable "to_proto" {
+  type = string
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf", gen.WithProto("test.v1", "example.com/test_module/testpb"))
+		assert.EqualError(t, err, `variable "to_proto": field ToProto has the name of the generated method Variables.ToProto, rename it`)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf")
+		assert.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`
+variable "server" {
+  type = object({ name = string })
+}
+
+variable "wrapper" {
+  type = object({ server_from_proto = object({ name = string }) })
+}
+`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf", gen.WithProto("test.v1", "example.com/test_module/testpb"))
+		assert.EqualError(t, err, "object type ServerFromProto has the name of the generated function converting Server from protobuf, rename it")
+	})
+
+	t.Run("generates a command with a flag for every variable", func(t *testing.T) {
+		outDir := t.TempDir()
+		err := gen.GenerateTFModulePackage("../testdata/basic_tf_module", outDir, "test_module", "tf", gen.WithCLI("example.com/test_module"))
+		assert.NoError(t, err)
+
+		src, err := os.ReadFile(filepath.Join(outDir, "cmd", "test_module", "main.go"))
+		assert.NoError(t, err)
+		for _, expected := range []string{
+			"package main",
+			"m := test_module.NewTestModule(*workingDir)",
+			`fs.Var(terraform.SensitiveFlag(terraform.VariableFlag("sensitive_string", "string", &v.SensitiveString)), "sensitive_string", "the sensitive_string variable (` + "`string`" + `, required, sensitive)")`,
+		} {
+			assert.Contains(t, string(src), expected)
+		}
+
+		moduleDir := t.TempDir()
+		err = os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte(`variable "working-dir" {}`), 0o644)
+		assert.NoError(t, err)
+
+		err = gen.GenerateTFModulePackage(moduleDir, t.TempDir(), "test_module", "tf", gen.WithCLI("example.com/test_module"))
+		assert.EqualError(t, err, `variable "working-dir": the generated command has a -working-dir flag of its own, rename the variable`)
+	})
+
+	t.Run("generates doc comments from the module", func(t *testing.T) {
+		src := generateBasicModule(t)
+		for _, expected := range []string{
+			"// Package test_module runs the test_module Terraform module.\n//\n// # Basic module\n",
+			"// Environment holds the environment variable.\n //\n // The environment to deploy to\n //\n // - Terraform type: string\n // - Default: \"dev\"\n Environment string",
+			"// Secret holds the JSON encoding of the secret output.\n //\n // - Sensitive\n",
+		} {
+			assert.Contains(t, src, expected)
+		}
+	})
+
+	t.Run("resolves the terraform executable to an absolute path", func(t *testing.T) {
+		src := generateBasicModule(t)
+		assert.Contains(t, src, "execPath, err = filepath.Abs(execPath)")
+	})
+
+	t.Run("generates terraform.Dynamic for any", func(t *testing.T) {
+		src := generateBasicModule(t, gen.WithAnyType(gen.AnyDynamic))
+		for _, expected := range []string{
+			"Annotations map[string]terraform.Dynamic `json:\"annotations,omitempty\"",
+			`Extra: terraform.Dynamic("{\"ratio\":0.5,\"tags\":[\"a\",null]}"),`,
+		} {
+			assert.Contains(t, src, expected)
+		}
+	})
+}
+
+// TestGeneratedPackage builds and vets the packages generated for
+// testdata/basic_tf_module with different options, and runs the tests of
+// testdata/generated_tests against them: those of the directory itself in
+// every package, and those of the subdirectory named after the options in
+// that package.
+func TestGeneratedPackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated packages with the go command")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	for name, opts := range map[string][]gen.Option{
+		"interface": {gen.WithTags(gen.TagYAML, gen.TagTOML, gen.TagMapstructure)},
+		"dynamic":   {gen.WithAnyType(gen.AnyDynamic)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// The package is generated within this module, under a testdata
+			// directory that ./... patterns leave out, so that it can import
+			// the terraform package
+			dir, err := os.MkdirTemp("../testdata", "build_")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			pkgDir := filepath.ToSlash(filepath.Join(filepath.Base(dir), "test_module"))
+			opts := append(opts, gen.WithCLI("github.com/lolabyte/tf2go/testdata/"+pkgDir))
+			err = gen.GenerateTFModulePackage("../testdata/basic_tf_module", filepath.Join(dir, "test_module"), "test_module", "tf", opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			tests, err := filepath.Glob("../testdata/generated_tests/*_test.go")
+			assert.NoError(t, err)
+			variantTests, err := filepath.Glob(filepath.Join("../testdata/generated_tests", name, "*_test.go"))
+			assert.NoError(t, err)
+			for _, path := range append(tests, variantTests...) {
+				copyFile(t, path, filepath.Join(dir, "test_module"))
+			}
+			cmdTests, err := filepath.Glob("../testdata/generated_tests/cmd/*_test.go")
+			assert.NoError(t, err)
+			for _, path := range cmdTests {
+				copyFile(t, path, filepath.Join(dir, "test_module", "cmd", "test_module"))
+			}
+
+			for _, args := range [][]string{{"build", "./testdata/" + pkgDir + "/..."}, {"vet", "./testdata/" + pkgDir + "/..."}, {"test", "./testdata/" + pkgDir + "/..."}} {
+				cmd := exec.Command(goCmd, args...)
+				cmd.Dir = ".."
+				out, err := cmd.CombinedOutput()
+				assert.NoError(t, err, "go %s:\n%s", args[0], out)
+			}
+		})
+	}
+}
+
+// copyFile copies the file at path into dir.
+func copyFile(t *testing.T, path, dir string) {
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, filepath.Base(path)), b, 0o644))
+}
+
+// captureStderr returns what f writes to os.Stderr.
+func captureStderr(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stderr := os.Stderr
+	os.Stderr = w
+	f()
+	os.Stderr = stderr
+	assert.NoError(t, w.Close())
+
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+// generateBasicModule generates a package for testdata/basic_tf_module and
+// returns its source, with runs of spaces collapsed to undo gofmt's alignment.
+func generateBasicModule(t *testing.T, opts ...gen.Option) string {
+	outDir := t.TempDir()
+	err := gen.GenerateTFModulePackage("../testdata/basic_tf_module", outDir, "test_module", "tf", opts...)
+	assert.NoError(t, err)
+
+	src, err := os.ReadFile(filepath.Join(outDir, "test_module.go"))
+	assert.NoError(t, err)
+
+	return regexp.MustCompile(`[ \t]+`).ReplaceAllString(string(src), " ")
 }
@@ -0,0 +1,436 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/printer"
+	tftoken "github.com/lolabyte/tf2go/terraform/token"
+)
+
+// goField is a field of a Go struct as encoding/json sees it, which becomes
+// a Terraform variable or object attribute. Pointer fields and fields left
+// out of the encoding when empty are optional, unless their tf2go struct tag
+// says otherwise.
+type goField struct {
+	field     *types.Var
+	name      string
+	optional  bool
+	sensitive bool
+
+	// depth is how deeply the field is embedded, and tagged whether its
+	// json tag names it, which decide between fields with the same name
+	depth  int
+	tagged bool
+}
+
+// GenerateVariablesTF returns a variables.tf declaring a variable for every
+// field of the struct type typeName of the Go package pkgPath, an import path
+// or a directory, such that the JSON encoding of the struct is a valid
+// .tfvars.json for it.
+//
+// Fields are named and flattened like encoding/json does, so json tags are
+// honored. Pointer fields and omitempty fields become variables that default
+// to null, and those of nested structs optional() attributes. Fields named
+// like the meta-arguments Terraform reserves, such as count, are errors. The
+// tf2go struct tags of
+// generated types take precedence, so that they round trip. The doc comment
+// of a field becomes the description of its variable.
+func GenerateVariablesTF(pkgPath string, typeName string) ([]byte, error) {
+	pkg, files, err := loadGoPackage(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%s has no type %s", pkg.Path(), typeName)
+	}
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not a struct type", pkg.Path(), typeName)
+	}
+
+	docs := fieldDocs(files)
+	seen := map[types.Type]bool{obj.Type(): true}
+
+	var buf bytes.Buffer
+	for i, f := range structFields(st) {
+		if !hclsyntax.ValidIdentifier(f.name) {
+			return nil, fmt.Errorf("field %s: %q is not a valid variable name", f.field.Name(), f.name)
+		}
+		if reservedVariableNames[f.name] {
+			return nil, fmt.Errorf("field %s: %q is reserved by Terraform and can't name a variable", f.field.Name(), f.name)
+		}
+
+		typeExpr, err := terraformType(f.field.Type(), seen)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.field.Name(), err)
+		}
+
+		attrs := []kvpair{{"type", typeExpr}}
+		// The first sentence of the doc comments tf2go generates only repeats
+		// the name of the variable
+		doc := docs[f.field.Pos()]
+		doc = strings.TrimSpace(strings.TrimPrefix(doc, fmt.Sprintf("%s holds the %s variable.", f.field.Name(), f.name)))
+		if doc != "" {
+			attrs = append(attrs, kvpair{"description", &ast.StringLiteral{Token: tfKeyword(tftoken.STRING, doc), Value: doc}})
+		}
+		if f.optional {
+			attrs = append(attrs, kvpair{"default", &ast.NullLiteral{Token: tfKeyword(tftoken.NULL, "null")}})
+		}
+		if f.sensitive {
+			attrs = append(attrs, kvpair{"sensitive", &ast.Bool{Token: tfKeyword(tftoken.TRUE, "true"), Value: true}})
+		}
+
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeBlock(&buf, "variable", f.name, attrs)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// reservedVariableNames are the names Terraform doesn't allow variables to
+// have, as they are meta-arguments of module blocks.
+var reservedVariableNames = map[string]bool{
+	"count":      true,
+	"depends_on": true,
+	"for_each":   true,
+	"lifecycle":  true,
+	"locals":     true,
+	"providers":  true,
+	"source":     true,
+	"version":    true,
+}
+
+// loadGoPackage parses and type checks the Go package pkgPath, an import path
+// or a directory. Its dependencies are type checked from source, which
+// doesn't depend on the export data format of the Go toolchain in use.
+func loadGoPackage(pkgPath string) (*types.Package, []*goast.File, error) {
+	// build.Import rejects absolute paths, and directories are named by
+	// their path rather than an import path they may not have
+	var bp *build.Package
+	var err error
+	importPath := pkgPath
+	if build.IsLocalImport(pkgPath) || filepath.IsAbs(pkgPath) {
+		bp, err = build.ImportDir(pkgPath, 0)
+	} else {
+		bp, err = build.Import(pkgPath, ".", 0)
+		if bp != nil {
+			importPath = bp.ImportPath
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*goast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, f)
+	}
+
+	cfg := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := cfg.Check(importPath, fset, files, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkg, files, nil
+}
+
+// writeBlock writes a block with a label and the given attributes, aligned
+// like terraform fmt does.
+func writeBlock(buf *bytes.Buffer, blockType, label string, attrs []kvpair) {
+	width := 0
+	for _, attr := range attrs {
+		if len(attr.name) > width {
+			width = len(attr.name)
+		}
+	}
+
+	fmt.Fprintf(buf, "%s %s {\n", blockType, ast.Quote(label))
+	for _, attr := range attrs {
+		fmt.Fprintf(buf, "  %-*s = %s\n", width, attr.name, printer.Print(attr.value))
+	}
+	buf.WriteString("}\n")
+}
+
+// structFields returns the fields of st encoded by encoding/json, in order,
+// with the fields of embedded structs promoted. Of the fields with the same
+// name, only the shallowest is encoded, or the one with a json tag among
+// several as shallow; when that doesn't single out a field, none is.
+func structFields(st *types.Struct) []goField {
+	fields := embeddedFields(st, 0)
+
+	dominant := map[string]*goField{}
+	ambiguous := map[string]bool{}
+	for i := range fields {
+		f := &fields[i]
+		d, ok := dominant[f.name]
+		switch {
+		case !ok || f.depth < d.depth || (f.depth == d.depth && f.tagged && !d.tagged):
+			dominant[f.name] = f
+			ambiguous[f.name] = false
+		case f.depth == d.depth && f.tagged == d.tagged:
+			ambiguous[f.name] = true
+		}
+	}
+
+	var encoded []goField
+	for i := range fields {
+		if f := &fields[i]; dominant[f.name] == f && !ambiguous[f.name] {
+			encoded = append(encoded, *f)
+		}
+	}
+	return encoded
+}
+
+// embeddedFields returns the fields of st at the given depth of embedding,
+// followed by those of its embedded structs, whatever their names.
+func embeddedFields(st *types.Struct, depth int) []goField {
+	var fields, promoted []goField
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+
+		jsonTag := tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, jsonOpts, _ := strings.Cut(jsonTag, ",")
+
+		if v.Embedded() && name == "" {
+			t := v.Type()
+			if pointer, ok := t.(*types.Pointer); ok {
+				t = pointer.Elem()
+			}
+			if embedded, ok := t.Underlying().(*types.Struct); ok {
+				promoted = append(promoted, embeddedFields(embedded, depth+1)...)
+				continue
+			}
+		}
+		if !v.Exported() {
+			continue
+		}
+		f := goField{field: v, name: name, depth: depth, tagged: name != ""}
+		if name == "" {
+			f.name = v.Name()
+		}
+
+		// The tf2go tag of generated types records whether a field is
+		// required, as required bools are pointers too
+		_, f.optional = v.Type().(*types.Pointer)
+		f.optional = f.optional || containsString(strings.Split(jsonOpts, ","), "omitempty")
+		if tf2go, ok := tag.Lookup("tf2go"); ok {
+			opts := strings.Split(tf2go, ",")[1:]
+			f.optional = !containsString(opts, "required")
+			f.sensitive = containsString(opts, "sensitive")
+		}
+		fields = append(fields, f)
+	}
+
+	return append(fields, promoted...)
+}
+
+// terraformType returns the Terraform type expression of the values of the Go
+// type t. seen holds the struct types being converted, to reject recursive
+// types that have no Terraform equivalent.
+func terraformType(t types.Type, seen map[types.Type]bool) (ast.Expression, error) {
+	// Types with their own encoding are strings when they encode as text,
+	// such as time.Time, and anything otherwise
+	switch {
+	case implements(t, "MarshalText"):
+		return &ast.StringTypeLiteral{Token: tfKeyword(tftoken.STRING_TYPE, "string")}, nil
+	case implements(t, "MarshalJSON"):
+		return &ast.AnyTypeLiteral{Token: tfKeyword(tftoken.ANY_TYPE, "any")}, nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return &ast.BoolTypeLiteral{Token: tfKeyword(tftoken.BOOL_TYPE, "bool")}, nil
+		case u.Info()&(types.IsInteger|types.IsFloat) != 0:
+			return &ast.NumberTypeLiteral{Token: tfKeyword(tftoken.NUMBER_TYPE, "number")}, nil
+		case u.Info()&types.IsString != 0:
+			return &ast.StringTypeLiteral{Token: tfKeyword(tftoken.STRING_TYPE, "string")}, nil
+		}
+	case *types.Pointer:
+		return terraformType(u.Elem(), seen)
+	case *types.Interface:
+		return &ast.AnyTypeLiteral{Token: tfKeyword(tftoken.ANY_TYPE, "any")}, nil
+	case *types.Slice:
+		// encoding/json encodes []byte as a base64 string
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return &ast.StringTypeLiteral{Token: tfKeyword(tftoken.STRING_TYPE, "string")}, nil
+		}
+		elem, err := terraformType(u.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ListTypeLiteral{Token: tfKeyword(tftoken.LIST_TYPE, "list"), TypeExpression: elem}, nil
+	case *types.Array:
+		elem, err := terraformType(u.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ListTypeLiteral{Token: tfKeyword(tftoken.LIST_TYPE, "list"), TypeExpression: elem}, nil
+	case *types.Map:
+		if b, ok := u.Key().Underlying().(*types.Basic); !ok || b.Info()&types.IsString == 0 {
+			return nil, fmt.Errorf("unsupported map key type %s", u.Key())
+		}
+		elem, err := terraformType(u.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.MapTypeLiteral{Token: tfKeyword(tftoken.MAP_TYPE, "map"), TypeExpression: elem}, nil
+	case *types.Struct:
+		return objectType(t, u, seen)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// objectType returns the object type of the Go struct type t, whose optional
+// fields are optional() attributes.
+func objectType(t types.Type, st *types.Struct, seen map[types.Type]bool) (ast.Expression, error) {
+	if seen[t] {
+		return nil, fmt.Errorf("recursive type %s", t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	spec := &ast.ObjectLiteral{
+		Token:   tfKeyword(tftoken.LEFT_CURLY_BRACE, "{"),
+		KVPairs: make(map[ast.Expression]ast.Expression),
+	}
+	for _, f := range structFields(st) {
+		attrType, err := terraformType(f.field.Type(), seen)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.field.Name(), err)
+		}
+		if f.optional {
+			attrType = &ast.OptionalTypeLiteral{Token: tfKeyword(tftoken.OPTIONAL_TYPE, "optional"), TypeExpression: attrType}
+		}
+
+		var key ast.Expression = &ast.Identifier{Token: tfKeyword(tftoken.IDENT, f.name), Value: f.name}
+		if !hclsyntax.ValidIdentifier(f.name) {
+			key = &ast.StringLiteral{Token: tfKeyword(tftoken.STRING, f.name), Value: f.name}
+		}
+		spec.KVPairs[key] = attrType
+	}
+
+	return &ast.ObjectTypeLiteral{Token: tfKeyword(tftoken.OBJECT_TYPE, "object"), ObjectSpec: spec}, nil
+}
+
+// implements reports whether t or a pointer to it has the method name, as
+// the encoding.TextMarshaler and json.Marshaler interfaces are looked up by
+// encoding/json.
+func implements(t types.Type, name string) bool {
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// fieldDocs returns the doc comments of the struct fields declared in files,
+// by the position of their name, as Terraform descriptions: paragraphs are
+// joined into single lines.
+func fieldDocs(files []*goast.File) map[token.Pos]string {
+	docs := map[token.Pos]string{}
+	for _, file := range files {
+		goast.Inspect(file, func(n goast.Node) bool {
+			field, ok := n.(*goast.Field)
+			if !ok {
+				return true
+			}
+
+			group := field.Doc
+			if group == nil {
+				group = field.Comment
+			}
+			doc := description(group.Text())
+			for _, name := range field.Names {
+				docs[name.Pos()] = doc
+			}
+			// An embedded field has no name, and is positioned at its type
+			if len(field.Names) == 0 {
+				docs[field.Type.Pos()] = doc
+			}
+			return true
+		})
+	}
+	return docs
+}
+
+// description joins the lines of each paragraph of a comment, which are
+// wrapped in Go source but not in a Terraform description, and of each list
+// item, which keeps its own line. The lists of the
+// Terraform type and default of a variable in the doc comments tf2go
+// generates are left out, as the variable declares them anyway.
+func description(comment string) string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(comment), "\n\n") {
+		if isGeneratedList(p) {
+			continue
+		}
+
+		var lines []string
+		for _, line := range strings.Split(p, "\n") {
+			line = strings.Join(strings.Fields(line), " ")
+			if len(lines) == 0 || strings.HasPrefix(line, "- ") {
+				lines = append(lines, line)
+			} else {
+				lines[len(lines)-1] += " " + line
+			}
+		}
+		paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// isGeneratedList reports whether every line of the paragraph p is an item
+// of the lists variableDoc and attributeDoc generate.
+func isGeneratedList(p string) bool {
+	for _, line := range strings.Split(p, "\n") {
+		switch {
+		case line == "  - Required", line == "  - Optional", line == "  - Sensitive":
+		case strings.HasPrefix(line, "  - Terraform type: "), strings.HasPrefix(line, "  - Default: "):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func tfKeyword(tokenType tftoken.TokenType, literal string) tftoken.Token {
+	return tftoken.Token{Type: tokenType, Literal: literal}
+}
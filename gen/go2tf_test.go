@@ -0,0 +1,122 @@
+package gen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lolabyte/tf2go/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateVariablesTF(t *testing.T) {
+	t.Run("declares a variable for every field", func(t *testing.T) {
+		b, err := gen.GenerateVariablesTF("../testdata/go2tf", "Config")
+		assert.NoError(t, err)
+		assert.Equal(t, `variable "region" {
+  type        = string
+  description = "Region is the region to deploy the service to."
+}
+
+variable "Replicas" {
+  type = number
+}
+
+variable "mode" {
+  type = string
+}
+
+variable "enabled" {
+  type    = bool
+  default = null
+}
+
+variable "zone" {
+  type    = string
+  default = null
+}
+
+variable "labels" {
+  type = map(string)
+}
+
+variable "servers" {
+  type = list(object({cert = string, created_at = string, enabled = bool, name = string, ports = list(number), tags = optional(list(string)), timeout = number, weight = optional(number)}))
+}
+
+variable "extra" {
+  type = any
+}
+
+variable "password" {
+  type      = string
+  sensitive = true
+}
+
+variable "environment" {
+  type        = string
+  description = "Environment is the environment to deploy to."
+}
+`, string(b))
+
+		// The variables must be a module tf2go can generate a package for
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "variables.tf"), b, 0o644))
+		_, err = gen.GenerateDocs(dir, "config")
+		assert.NoError(t, err)
+	})
+
+	t.Run("keeps the lists of doc comments but those tf2go generates", func(t *testing.T) {
+		b, err := gen.GenerateVariablesTF("../testdata/go2tf", "Listed")
+		assert.NoError(t, err)
+		assert.Equal(t, `variable "steps" {
+  type        = list(string)
+  description = "Steps are the steps to run:\n\n- build\n- deploy"
+}
+
+variable "timeout" {
+  type    = number
+  default = null
+}
+`, string(b))
+	})
+
+	t.Run("leaves out fields encoding/json doesn't encode for their name", func(t *testing.T) {
+		b, err := gen.GenerateVariablesTF("../testdata/go2tf", "Conflicting")
+		assert.NoError(t, err)
+		assert.Equal(t, `variable "name" {
+  type = string
+}
+
+variable "Team" {
+  type = string
+}
+`, string(b))
+	})
+
+	t.Run("returns an error for types without a Terraform equivalent", func(t *testing.T) {
+		_, err := gen.GenerateVariablesTF("../testdata/go2tf", "Tree")
+		assert.EqualError(t, err, "field Children: recursive type ../testdata/go2tf.Tree")
+
+		_, err = gen.GenerateVariablesTF("../testdata/go2tf", "Mode")
+		assert.EqualError(t, err, "../testdata/go2tf.Mode is not a struct type")
+
+		_, err = gen.GenerateVariablesTF("../testdata/go2tf", "Missing")
+		assert.EqualError(t, err, "../testdata/go2tf has no type Missing")
+
+		_, err = gen.GenerateVariablesTF("../testdata/go2tf", "Reserved")
+		assert.EqualError(t, err, `field Count: "count" is reserved by Terraform and can't name a variable`)
+	})
+
+	t.Run("reads packages from absolute directories", func(t *testing.T) {
+		dir, err := filepath.Abs("../testdata/go2tf")
+		assert.NoError(t, err)
+
+		b, err := gen.GenerateVariablesTF(dir, "Listed")
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), `variable "steps" {`)
+
+		_, err = gen.GenerateVariablesTF(dir, "Mode")
+		assert.EqualError(t, err, dir+".Mode is not a struct type")
+	})
+}
@@ -0,0 +1,103 @@
+package gen
+
+import (
+	"fmt"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/utils"
+)
+
+// generatedMethods returns the names of the methods generated for the struct
+// structName, which is Variables, Outputs or an object struct. Go doesn't
+// allow a field with the name of a method.
+func generatedMethods(structName string, opts *options) []string {
+	methods := []string{"DeepCopy", "Diff", "Equal"}
+	if opts.protoGoPackage != "" {
+		methods = append(methods, "ToProto")
+	}
+	switch structName {
+	case "Variables":
+		methods = append(methods, "MarshalJSON", "NullVariables", "SetNull", "Validate", "WriteTFVarJSON", "WriteTFVars")
+	case "Outputs":
+		methods = append(methods, "WriteTFOutputJSON")
+	default:
+		return methods
+	}
+
+	if opts.hasTag(TagYAML) {
+		methods = append(methods, "SaveYAML")
+	}
+	if opts.hasTag(TagTOML) {
+		methods = append(methods, "SaveTOML")
+	}
+	return methods
+}
+
+// checkMethodCollision returns an error when fieldName is the name of a
+// method generated for the struct structName.
+func checkMethodCollision(structName, fieldName string, opts *options) error {
+	for _, m := range generatedMethods(structName, opts) {
+		if m == fieldName {
+			return fmt.Errorf("field %s has the name of the generated method %s.%s, rename it", fieldName, structName, m)
+		}
+	}
+	return nil
+}
+
+// attributeMethodCollisions returns the attributes of the object types in
+// typeExpr whose fields have the name of a method of their struct, named as
+// in eval.
+func attributeMethodCollisions(opts *options, typeExpr ast.Node, name string) []string {
+	var errs []string
+	for _, obj := range objectStructs(typeExpr, name) {
+		for _, kv := range obj.node.ObjectSpec.(*ast.ObjectLiteral).SortedKVPairs() {
+			attr := ast.KeyName(kv.Key)
+			if err := checkMethodCollision(obj.name, utils.SnakeToCamel(attr), opts); err != nil {
+				errs = append(errs, fmt.Sprintf("attribute %q: %v", attr, err))
+			}
+		}
+	}
+	return errs
+}
+
+// objectStruct is an object type of a variable and the name of the struct
+// eval generates for it.
+type objectStruct struct {
+	node *ast.ObjectTypeLiteral
+	name string
+}
+
+// objectStructs returns the object types in typeExpr, the type of the
+// variable or attribute name, outermost first.
+func objectStructs(typeExpr ast.Node, name string) []objectStruct {
+	// Objects are named after the attribute holding them, which is known
+	// once the object of the attribute is visited
+	names := map[*ast.ObjectTypeLiteral]string{}
+	nameObjects(typeExpr, name, names)
+
+	var structs []objectStruct
+	ast.Inspect(typeExpr, func(n ast.Node) bool {
+		obj, ok := n.(*ast.ObjectTypeLiteral)
+		if !ok {
+			return true
+		}
+		structs = append(structs, objectStruct{obj, utils.SnakeToCamel(names[obj])})
+		for _, kv := range obj.ObjectSpec.(*ast.ObjectLiteral).SortedKVPairs() {
+			nameObjects(kv.Value, ast.KeyName(kv.Key), names)
+		}
+		return true
+	})
+	return structs
+}
+
+// nameObjects records name as the name of the outermost object types in
+// typeExpr, which are those within its lists, maps and optional().
+func nameObjects(typeExpr ast.Node, name string, names map[*ast.ObjectTypeLiteral]string) {
+	ast.Inspect(typeExpr, func(n ast.Node) bool {
+		if obj, ok := n.(*ast.ObjectTypeLiteral); ok {
+			names[obj] = name
+			return false
+		}
+		return true
+	})
+}
@@ -0,0 +1,131 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/printer"
+)
+
+// variableField is a field of the generated Variables struct.
+type variableField struct {
+	variable *tfconfig.Variable
+	name     string
+	typ      *j.Statement
+	tfType   ast.Node
+
+	// defaultValue is the default value of the variable, and defaultCode
+	// the Go expression of it, or nil when the variable has none
+	defaultValue ast.Expression
+	defaultCode  j.Code
+}
+
+// outputField is a field of the generated Outputs struct.
+type outputField struct {
+	output *tfconfig.Output
+	name   string
+}
+
+// variableFields returns the fields of the generated Variables struct,
+// ordered by the name of their variable. Every variable with an invalid type
+// or default is reported at once.
+func variableFields(mod *tfconfig.Module, opts *options) ([]variableField, error) {
+	// Sort alphabetically
+	var variables []*tfconfig.Variable
+	for _, v := range mod.Variables {
+		variables = append(variables, v)
+	}
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+
+	var typeErrors []string
+	var fields []variableField
+	structs := make(map[string]structOrigin)
+	for _, v := range variables {
+		t, err := astNodeType(v, opts)
+		if err != nil {
+			// Carry on so that every invalid variable is reported at once
+			typeErrors = append(typeErrors, err.Error())
+			continue
+		}
+
+		field := variableField{variable: v, name: structFieldNameForVar(v), typ: goType(opts, t, v.Name), tfType: t}
+		if err := checkMethodCollision("Variables", field.name, opts); err != nil {
+			typeErrors = append(typeErrors, fmt.Sprintf("variable %q: %v", v.Name, err))
+		}
+		for _, err := range attributeMethodCollisions(opts, t, v.Name) {
+			typeErrors = append(typeErrors, fmt.Sprintf("variable %q: %s", v.Name, err))
+		}
+		for _, err := range structCollisions(opts, t, v.Name, structs) {
+			typeErrors = append(typeErrors, fmt.Sprintf("variable %q: %s", v.Name, err))
+		}
+		if !v.Required && v.Default != nil {
+			field.defaultValue, err = astNodeDefault(v)
+			if err == nil {
+				field.defaultCode, err = goValue(opts, t, field.defaultValue, v.Name)
+				if err != nil {
+					err = fmt.Errorf("invalid default for variable %q: %v", v.Name, err)
+				}
+			}
+			if err != nil {
+				typeErrors = append(typeErrors, err.Error())
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	if len(typeErrors) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(typeErrors, "\n"))
+	}
+
+	return fields, nil
+}
+
+// structOrigin is the first object type generating a struct, and the Go
+// source of the struct.
+type structOrigin struct {
+	variable string
+	node     *ast.ObjectTypeLiteral
+	source   string
+}
+
+// structCollisions reports the object types in typeExpr, the type of the
+// variable name, whose struct has the name of the struct of a different
+// object type in structs, which holds those of the variables before it.
+// Object types generating the same struct share it.
+func structCollisions(opts *options, typeExpr ast.Node, name string, structs map[string]structOrigin) []string {
+	var errs []string
+	for _, obj := range objectStructs(typeExpr, name) {
+		source := fmt.Sprintf("%#v", j.Struct(objectFields(nil, opts, obj.node)...))
+		origin, ok := structs[obj.name]
+		if !ok {
+			structs[obj.name] = structOrigin{name, obj.node, source}
+			continue
+		}
+		if origin.source != source {
+			errs = append(errs, fmt.Sprintf("struct %s of the type %s differs from the struct %s of variable %q, of the type %s, rename one of them",
+				obj.name, printer.Print(obj.node), obj.name, origin.variable, printer.Print(origin.node)))
+		}
+	}
+	return errs
+}
+
+// outputFields returns the fields of the generated Outputs struct, ordered by
+// the name of their output.
+func outputFields(mod *tfconfig.Module) []outputField {
+	// Sort alphabetically
+	var outputs []*tfconfig.Output
+	for _, o := range mod.Outputs {
+		outputs = append(outputs, o)
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+
+	fields := make([]outputField, 0, len(outputs))
+	for _, o := range outputs {
+		fields = append(fields, outputField{output: o, name: structFieldNameForOutput(o)})
+	}
+	return fields
+}
@@ -0,0 +1,237 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var variableBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+	},
+}
+
+var nullableAttributeSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "nullable"},
+	},
+}
+
+var typeAttributeSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "type"},
+	},
+}
+
+// loadNullable reads the nullable argument of every variable in the module at
+// dir, which tfconfig doesn't report. Variables are nullable unless they are
+// declared with nullable = false.
+func loadNullable(dir string, mod *tfconfig.Module) (map[string]bool, error) {
+	nullable := make(map[string]bool, len(mod.Variables))
+	for name := range mod.Variables {
+		nullable[name] = true
+	}
+
+	blocks, diags := parseVariableBlocks(dir)
+	for _, block := range blocks {
+		attrs, _, attrDiags := block.Body.PartialContent(nullableAttributeSchema)
+		diags = append(diags, attrDiags...)
+
+		attr, ok := attrs.Attributes["nullable"]
+		if !ok {
+			continue
+		}
+
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+		if val.Type() != cty.Bool || val.IsNull() {
+			return nil, fmt.Errorf("%s: nullable for variable %q must be true or false", attr.Range, block.Labels[0])
+		}
+
+		nullable[block.Labels[0]] = val.True()
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return nullable, nil
+}
+
+// loadQuotedTypes returns the variables of the module at dir whose type is a
+// quoted string, the deprecated Terraform 0.11 syntax. tfconfig unquotes
+// them, so they can't be told apart from the types they hold otherwise. The
+// types of *.tf.json files are always strings, and aren't quoted types.
+func loadQuotedTypes(dir string) (map[string]bool, error) {
+	quoted := make(map[string]bool)
+
+	blocks, diags := parseVariableBlocks(dir)
+	for _, block := range blocks {
+		attrs, _, attrDiags := block.Body.PartialContent(typeAttributeSchema)
+		diags = append(diags, attrDiags...)
+
+		attr, ok := attrs.Attributes["type"]
+		if !ok {
+			continue
+		}
+		if _, ok := attr.Expr.(*hclsyntax.TemplateExpr); ok {
+			quoted[block.Labels[0]] = true
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return quoted, nil
+}
+
+// parseVariableBlocks parses the variable blocks of the *.tf and *.tf.json
+// files in dir.
+func parseVariableBlocks(dir string) ([]*hcl.Block, hcl.Diagnostics) {
+	hclFiles, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, hcl.Diagnostics{{Severity: hcl.DiagError, Summary: err.Error()}}
+	}
+	jsonFiles, err := filepath.Glob(filepath.Join(dir, "*.tf.json"))
+	if err != nil {
+		return nil, hcl.Diagnostics{{Severity: hcl.DiagError, Summary: err.Error()}}
+	}
+
+	parser := hclparse.NewParser()
+	var blocks []*hcl.Block
+	var diags hcl.Diagnostics
+	for _, filename := range append(hclFiles, jsonFiles...) {
+		var file *hcl.File
+		var fileDiags hcl.Diagnostics
+		if strings.HasSuffix(filename, ".json") {
+			file, fileDiags = parser.ParseJSONFile(filename)
+		} else {
+			file, fileDiags = parser.ParseHCLFile(filename)
+		}
+		diags = append(diags, fileDiags...)
+		if file == nil {
+			continue
+		}
+
+		content, _, contentDiags := file.Body.PartialContent(variableBlockSchema)
+		diags = append(diags, contentDiags...)
+		blocks = append(blocks, content.Blocks...)
+	}
+
+	return blocks, diags
+}
+
+// generateNullHandling generates the methods that let variables be set to
+// null explicitly rather than omitted, so that Terraform uses null instead of
+// their default, and that reject null for unknown variables and those that
+// aren't nullable. Variables of the any type generated as terraform.Dynamic can
+// also be set to null by holding it.
+func generateNullHandling(src *j.File, nullable map[string]bool, fields []variableField, opts *options) {
+	names := make([]string, 0, len(nullable))
+	for name := range nullable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := j.Dict{}
+	for _, name := range names {
+		values[j.Lit(name)] = j.Lit(nullable[name])
+	}
+
+	src.Comment("variableNullable records whether each variable accepts null.")
+	src.Var().Id("variableNullable").Op("=").Map(j.String()).Bool().Values(values).Line()
+
+	src.Comment("SetNull sets the variable with the given Terraform name to null, rather than omitting it so")
+	src.Comment("that its default applies. Null takes precedence over the value of the variable's field. It")
+	src.Comment("returns an error for unknown variables and for those that aren't nullable.")
+	src.Func().Params(
+		j.Id("v").Op("*").Id("Variables"),
+	).Id("SetNull").Params(
+		j.Id("name").String(),
+	).Error().Block(
+		j.List(j.Id("nullable"), j.Id("ok")).Op(":=").Id("variableNullable").Index(j.Id("name")),
+		j.If(j.Op("!").Id("ok")).Block(
+			j.Return(j.Qual("fmt", "Errorf").Call(j.Lit("unknown variable %q"), j.Id("name"))),
+		),
+		j.If(j.Op("!").Id("nullable")).Block(
+			j.Return(j.Qual("fmt", "Errorf").Call(j.Lit("variable %q is not nullable"), j.Id("name"))),
+		).Line(),
+
+		j.If(j.Id("v").Dot("nulls").Op("==").Nil()).Block(
+			j.Id("v").Dot("nulls").Op("=").Make(j.Map(j.String()).Bool()),
+		),
+		j.Id("v").Dot("nulls").Index(j.Id("name")).Op("=").True(),
+		j.Return(j.Nil()),
+	).Line()
+
+	var dynamicNullChecks []j.Code
+	for _, f := range fields {
+		if nullable[f.variable.Name] || !isDynamicType(opts, f.tfType) {
+			continue
+		}
+		value := j.Id("v").Dot(f.name)
+		dynamicNullChecks = append(dynamicNullChecks, j.If(j.Len(value).Op(">").Lit(0).Op("&&").Add(value).Dot("IsNull").Call()).Block(
+			j.Return(j.Qual("fmt", "Errorf").Call(j.Lit("variable %q is not nullable"), j.Lit(f.variable.Name))),
+		).Line())
+	}
+
+	src.Comment("Validate reports variables holding null that aren't nullable, which SetNull rejects but values")
+	src.Comment("of the any type can hold as well.")
+	src.Func().Params(
+		j.Id("v").Id("Variables"),
+	).Id("Validate").Params().Error().Block(
+		j.Add(dynamicNullChecks...),
+		j.Return(j.Nil()),
+	).Line()
+
+	src.Comment("MarshalJSON encodes the variables as Terraform variable definitions, including those set to null.")
+	src.Func().Params(
+		j.Id("v").Id("Variables"),
+	).Id("MarshalJSON").Params().Parens(j.List(j.Index().Byte(), j.Error())).Block(
+		j.If(j.Err().Op(":=").Id("v").Dot("Validate").Call(), j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Nil(), j.Err()),
+		).Line(),
+
+		j.Type().Id("variables").Id("Variables"),
+		j.List(j.Id("b"), j.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(j.Id("variables").Call(j.Id("v"))),
+		j.If(j.Err().Op("!=").Nil().Op("||").Len(j.Id("v").Dot("nulls")).Op("==").Lit(0)).Block(
+			j.Return(j.Id("b"), j.Err()),
+		).Line(),
+
+		j.Var().Id("fields").Map(j.String()).Qual("encoding/json", "RawMessage"),
+		j.If(j.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(j.Id("b"), j.Op("&").Id("fields")), j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Nil(), j.Err()),
+		),
+		j.For(j.Id("name").Op(":=").Range().Id("v").Dot("nulls")).Block(
+			j.Id("fields").Index(j.Id("name")).Op("=").Qual("encoding/json", "RawMessage").Call(j.Lit("null")),
+		).Line(),
+
+		j.Return(j.Qual("encoding/json", "Marshal").Call(j.Id("fields"))),
+	).Line()
+}
+
+// isDynamicType reports whether eval generates terraform.Dynamic for
+// typeExpr.
+func isDynamicType(opts *options, typeExpr ast.Node) bool {
+	if t, ok := typeExpr.(*ast.Type); ok {
+		for _, s := range t.Statements {
+			return isDynamicType(opts, s.(*ast.ExpressionStatement).Expression)
+		}
+	}
+	_, ok := typeExpr.(*ast.AnyTypeLiteral)
+	return ok && opts.anyType == AnyDynamic
+}
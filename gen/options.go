@@ -0,0 +1,141 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+)
+
+// Option configures the generated package.
+type Option func(*options)
+
+type options struct {
+	anyType AnyType
+	tags    []TagFormat
+
+	protoPackage   string
+	protoGoPackage string
+
+	cliImportPath string
+
+	// fractional holds the number types with a fractional default value,
+	// which are generated as float64 rather than int64
+	fractional map[*ast.NumberTypeLiteral]bool
+
+	// quotedTypes holds the variables whose type is quoted, as in Terraform
+	// 0.11, which tfconfig reports unquoted
+	quotedTypes map[string]bool
+
+	// structs holds the names of the object structs already generated,
+	// which object types of the same shape share
+	structs map[string]bool
+
+	// warn receives the warnings about the module, which are printed to
+	// stderr when it is nil
+	warn func(string)
+}
+
+// warning reports a problem with the module that doesn't stop generation. A
+// nil o discards it, for when the warning has already been reported.
+func (o *options) warning(format string, args ...interface{}) {
+	if o == nil {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if o.warn != nil {
+		o.warn(msg)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+}
+
+// isFloat reports whether values of the number type n are generated as
+// float64, because one of its default values is fractional.
+func (o *options) isFloat(n *ast.NumberTypeLiteral) bool {
+	return o != nil && o.fractional[n]
+}
+
+// hasTag reports whether the format was requested with WithTags.
+func (o *options) hasTag(format TagFormat) bool {
+	for _, t := range o.tags {
+		if t == format {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyType selects the Go type generated for values of Terraform's any type.
+type AnyType int
+
+const (
+	// AnyInterface generates interface{}, holding whatever encoding/json
+	// decodes the value to.
+	AnyInterface AnyType = iota
+
+	// AnyDynamic generates terraform.Dynamic, which keeps the JSON encoding
+	// of the value and offers typed accessors to decode it.
+	AnyDynamic
+)
+
+// WithAnyType sets the Go type generated for values of Terraform's any type.
+// The default is AnyInterface.
+func WithAnyType(t AnyType) Option {
+	return func(o *options) {
+		o.anyType = t
+	}
+}
+
+// TagFormat is a file format Variables and Outputs are read from and written
+// to besides JSON, keyed by the Terraform names.
+type TagFormat string
+
+const (
+	// TagYAML generates helpers to load and save Variables and Outputs as
+	// YAML files. They go through the JSON encoding of the types, which
+	// handles nulls and values of the any type, so no yaml tags are
+	// generated for yaml.Marshal to use instead.
+	TagYAML TagFormat = "yaml"
+
+	// TagTOML generates helpers to load and save Variables and Outputs as
+	// TOML files, through their JSON encoding like TagYAML, without toml
+	// tags.
+	TagTOML TagFormat = "toml"
+
+	// TagMapstructure generates mapstructure tags, as used by viper.
+	TagMapstructure TagFormat = "mapstructure"
+)
+
+// WithTags adds support for the given formats: mapstructure tags on the
+// generated fields, or file helpers for YAML and TOML.
+func WithTags(formats ...TagFormat) Option {
+	return func(o *options) {
+		o.tags = append(o.tags, formats...)
+	}
+}
+
+// WithProto also generates a .proto file with messages for Variables, Outputs
+// and every object type in the protobuf package protoPackage, and
+// conversions between the generated types and the types protoc-gen-go
+// generates from it into the Go package goPackage, given by its import path.
+// Fields are numbered by a hash of their name alone, so that adding or
+// removing variables keeps the messages wire compatible, and generation fails
+// in the unlikely case that two fields of a message get the same number.
+func WithProto(protoPackage, goPackage string) Option {
+	return func(o *options) {
+		o.protoPackage = protoPackage
+		o.protoGoPackage = goPackage
+	}
+}
+
+// WithCLI also generates a command in cmd/<package> under the output
+// directory, which runs the embedded module with plan, apply, destroy and
+// output subcommands and takes every variable as a flag. apply and destroy
+// show the plan and ask for confirmation unless run with -auto-approve.
+// importPath is the import path of the generated package.
+func WithCLI(importPath string) Option {
+	return func(o *options) {
+		o.cliImportPath = importPath
+	}
+}
@@ -0,0 +1,345 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/protoconv"
+	"github.com/lolabyte/tf2go/utils"
+)
+
+var protoPackagePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+type protoMessage struct {
+	name     string
+	comment  string
+	fields   []protoField
+	isObject bool // generated for an object type, which has a Go struct
+}
+
+type protoField struct {
+	comment string
+	label   string // optional, repeated or empty
+	typ     string
+	name    string
+}
+
+// protoFile collects the messages of the .proto file generated for a module.
+type protoFile struct {
+	opts      *options
+	messages  []*protoMessage
+	seen      map[string]*protoMessage
+	usesValue bool
+
+	// err is the first type that can't be given a message, reported once
+	// every variable is visited
+	err error
+}
+
+// generateProto generates the .proto file for a module, with messages for
+// Variables, Outputs and every object type, and the Go source of the
+// conversions between the generated types and the types protoc-gen-go
+// generates from it.
+func generateProto(mod *tfconfig.Module, opts *options, packageName string) ([]byte, *j.File, error) {
+	// The variables are parsed again, with their warnings already reported
+	quiet := *opts
+	quiet.warn = func(string) {}
+	f := &protoFile{opts: &quiet, seen: make(map[string]*protoMessage)}
+
+	var variables []*tfconfig.Variable
+	for _, v := range mod.Variables {
+		variables = append(variables, v)
+	}
+	sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+
+	vars := &protoMessage{name: "Variables", comment: "Variables holds the input variables of the module."}
+	for _, v := range variables {
+		t, err := astNodeType(v, f.opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		label, typ := f.fieldType(t, v.Name)
+		vars.fields = append(vars.fields, protoField{v.Description, label, typ, protoconv.FieldName(v.Name)})
+	}
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+
+	var outputs []*tfconfig.Output
+	for _, o := range mod.Outputs {
+		outputs = append(outputs, o)
+	}
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Name < outputs[j].Name })
+
+	outs := &protoMessage{name: "Outputs", comment: "Outputs holds the outputs of the module."}
+	for _, o := range outputs {
+		f.usesValue = true
+		outs.fields = append(outs.fields, protoField{o.Description, "", protoconv.ValueMessage, protoconv.FieldName(o.Name)})
+	}
+
+	f.messages = append([]*protoMessage{vars, outs}, f.messages...)
+
+	// Each Go type gets a function converting from its message, named after
+	// it, which another object struct can't have the name of
+	types := make(map[string]bool)
+	for _, msg := range f.messages {
+		if msg.isObject {
+			types[msg.name] = true
+		}
+	}
+	for _, msg := range f.messages {
+		if (msg == vars || msg == outs || msg.isObject) && types[msg.name+"FromProto"] {
+			return nil, nil, fmt.Errorf("object type %sFromProto has the name of the generated function converting %s from protobuf, rename it", msg.name, msg.name)
+		}
+	}
+
+	protoSrc, err := f.source(opts.protoPackage, opts.protoGoPackage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return protoSrc, f.conversions(packageName, opts.protoGoPackage), nil
+}
+
+// fieldType returns the label and type of the message field holding values
+// of typeExpr.
+func (f *protoFile) fieldType(typeExpr ast.Node, name string) (string, string) {
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			return f.fieldType(s.(*ast.ExpressionStatement).Expression, name)
+		}
+	case *ast.BoolTypeLiteral:
+		// Bools are generated as *bool, which protoc-gen-go generates for
+		// optional bools too
+		return "optional", "bool"
+	case *ast.ListTypeLiteral:
+		return "repeated", f.elementType(node.TypeExpression, name)
+	case *ast.MapTypeLiteral:
+		return "", fmt.Sprintf("map<string, %s>", f.elementType(node.TypeExpression, name))
+	case *ast.OptionalTypeLiteral:
+		label, typ := f.fieldType(node.TypeExpression, name)
+		if label == "" && (typ == "int64" || typ == "double" || typ == "string") {
+			label = "optional"
+		}
+		return label, typ
+	}
+	return "", f.elementType(typeExpr, name)
+}
+
+// elementType returns the type of the elements of a list or map field holding
+// values of typeExpr, which is a wrapper message for lists and maps.
+func (f *protoFile) elementType(typeExpr ast.Node, name string) string {
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			return f.elementType(s.(*ast.ExpressionStatement).Expression, name)
+		}
+	case *ast.AnyTypeLiteral:
+		f.usesValue = true
+		return protoconv.ValueMessage
+	case *ast.BoolTypeLiteral:
+		return "bool"
+	case *ast.NumberTypeLiteral:
+		if f.opts.isFloat(node) {
+			return "double"
+		}
+		return "int64"
+	case *ast.StringTypeLiteral:
+		return "string"
+	case *ast.ListTypeLiteral, *ast.MapTypeLiteral:
+		wrapper := f.typeName(node, name)
+		label, typ := f.fieldType(node, name)
+		f.addMessage(&protoMessage{
+			name:    wrapper,
+			comment: fmt.Sprintf("%s wraps a value nested in a list or map.", wrapper),
+			fields:  []protoField{{"", label, typ, protoconv.WrapperField}},
+		})
+		return wrapper
+	case *ast.ObjectTypeLiteral:
+		msg := &protoMessage{
+			name:     utils.SnakeToCamel(name),
+			comment:  fmt.Sprintf("%s holds a value of the type %s.", utils.SnakeToCamel(name), node.String()),
+			isObject: true,
+		}
+		for _, kv := range node.ObjectSpec.(*ast.ObjectLiteral).SortedKVPairs() {
+			attr := ast.KeyName(kv.Key)
+			label, typ := f.fieldType(kv.Value, attr)
+			msg.fields = append(msg.fields, protoField{"", label, typ, protoconv.FieldName(attr)})
+		}
+		f.addMessage(msg)
+		return msg.name
+	}
+	return ""
+}
+
+// addMessage adds msg to the file, unless a message of the same name and
+// fields was already added for another value of the same type. A different
+// message of the same name is an error.
+func (f *protoFile) addMessage(msg *protoMessage) {
+	if existing, ok := f.seen[msg.name]; ok {
+		if !reflect.DeepEqual(existing.fields, msg.fields) && f.err == nil {
+			f.err = fmt.Errorf("message %s has different fields for different types, rename one of them", msg.name)
+		}
+		return
+	}
+	f.seen[msg.name] = msg
+	f.messages = append(f.messages, msg)
+}
+
+// typeName returns the name of the message holding values of typeExpr within
+// a list or map, such as ListOfInt64 for list(number).
+func (f *protoFile) typeName(typeExpr ast.Node, name string) string {
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			return f.typeName(s.(*ast.ExpressionStatement).Expression, name)
+		}
+	case *ast.AnyTypeLiteral:
+		return "Value"
+	case *ast.BoolTypeLiteral:
+		return "Bool"
+	case *ast.NumberTypeLiteral:
+		if f.opts.isFloat(node) {
+			return "Double"
+		}
+		return "Int64"
+	case *ast.StringTypeLiteral:
+		return "String"
+	case *ast.ListTypeLiteral:
+		return "ListOf" + f.typeName(node.TypeExpression, name)
+	case *ast.MapTypeLiteral:
+		return "MapOf" + f.typeName(node.TypeExpression, name)
+	}
+	return utils.SnakeToCamel(name)
+}
+
+// maxFieldNumber is the largest protobuf field number.
+const maxFieldNumber = 1<<29 - 1
+
+// fieldNumber returns the number of the field name, derived from a hash of
+// the name alone so that it doesn't change as fields are added to or removed
+// from the message, which keeps messages generated from different versions
+// of a module wire compatible. used holds the names of the fields already
+// numbered in the message, by number: two fields with the same number, which
+// is unlikely, are an error, as picking another number for one of them would
+// depend on the other fields.
+func fieldNumber(name string, used map[uint32]string) (uint32, error) {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	n := h.Sum32()%maxFieldNumber + 1
+	// 19000 to 19999 are reserved for the protobuf implementation
+	for n >= 19000 && n <= 19999 {
+		n = n%maxFieldNumber + 1
+	}
+	if other, ok := used[n]; ok {
+		return 0, fmt.Errorf("fields %s and %s both have the number %d, rename one of them", other, name, n)
+	}
+	used[n] = name
+	return n, nil
+}
+
+// source returns the text of the .proto file.
+func (f *protoFile) source(protoPackage, goPackage string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by tf2go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", protoPackage)
+	if f.usesValue {
+		fmt.Fprintf(&buf, "import \"google/protobuf/struct.proto\";\n\n")
+	}
+	fmt.Fprintf(&buf, "option go_package = %q;\n", goPackage)
+
+	for _, msg := range f.messages {
+		fmt.Fprintf(&buf, "\n// %s\nmessage %s {\n", msg.comment, msg.name)
+		used := make(map[uint32]string)
+		for _, field := range msg.fields {
+			if field.comment != "" {
+				for _, line := range strings.Split(strings.TrimSpace(field.comment), "\n") {
+					fmt.Fprintf(&buf, "  // %s\n", line)
+				}
+			}
+			typ := field.typ
+			if field.label != "" {
+				typ = field.label + " " + typ
+			}
+			number, err := fieldNumber(field.name, used)
+			if err != nil {
+				return nil, fmt.Errorf("message %s: %v", msg.name, err)
+			}
+			fmt.Fprintf(&buf, "  %s %s = %d;\n", typ, field.name, number)
+		}
+		fmt.Fprintf(&buf, "}\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// conversions returns the Go source of the conversions between the generated
+// types and the messages protoc-gen-go generates into goPackage.
+func (f *protoFile) conversions(packageName, goPackage string) *j.File {
+	src := j.NewFile(packageName)
+
+	for _, msg := range f.messages {
+		var recv, typ j.Code
+		switch {
+		case msg.name == "Variables":
+			recv, typ = j.Id("v").Id("Variables"), j.Id("Variables")
+		case msg.name == "Outputs":
+			recv, typ = j.Id("o").Id("Outputs"), j.Id("Outputs")
+		case msg.isObject:
+			recv, typ = j.Id("v").Op("*").Id(msg.name), j.Op("*").Id(msg.name)
+		default:
+			continue
+		}
+		v := j.Id(strings.ToLower(msg.name[:1]))
+		if msg.isObject {
+			v = j.Id("v")
+		}
+
+		src.Commentf("ToProto converts %s to its protobuf message.", msg.name)
+		src.Func().Params(recv).Id("ToProto").Params().Parens(j.List(j.Op("*").Qual(goPackage, msg.name), j.Error())).Block(
+			j.Id("m").Op(":=").New(j.Qual(goPackage, msg.name)),
+			j.If(
+				j.Err().Op(":=").Qual("github.com/lolabyte/tf2go/terraform/protoconv", "ToProto").Call(v, j.Id("m")),
+				j.Err().Op("!=").Nil(),
+			).Block(
+				j.Return(j.Nil(), j.Err()),
+			),
+			j.Return(j.Id("m"), j.Nil()),
+		).Line()
+
+		fromProto := msg.name + "FromProto"
+		src.Commentf("%s converts a protobuf message to %s.", fromProto, msg.name)
+		if msg.isObject {
+			src.Func().Id(fromProto).Params(
+				j.Id("m").Op("*").Qual(goPackage, msg.name),
+			).Parens(j.List(typ, j.Error())).Block(
+				j.If(j.Id("m").Op("==").Nil()).Block(
+					j.Return(j.Nil(), j.Nil()),
+				),
+				j.Id("v").Op(":=").New(j.Id(msg.name)),
+				j.Err().Op(":=").Qual("github.com/lolabyte/tf2go/terraform/protoconv", "FromProto").Call(j.Id("m"), j.Id("v")),
+				j.Return(j.Id("v"), j.Err()),
+			).Line()
+			continue
+		}
+		src.Func().Id(fromProto).Params(
+			j.Id("m").Op("*").Qual(goPackage, msg.name),
+		).Parens(j.List(typ, j.Error())).Block(
+			j.Var().Add(v).Add(typ),
+			j.Err().Op(":=").Qual("github.com/lolabyte/tf2go/terraform/protoconv", "FromProto").Call(j.Id("m"), j.Op("&").Add(v)),
+			j.Return(v, j.Err()),
+		).Line()
+	}
+
+	return src
+}
@@ -0,0 +1,246 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	j "github.com/dave/jennifer/jen"
+)
+
+// DefaultEmbedDir is the directory of the generated package the module is
+// embedded from when Options.EmbedDir is empty.
+const DefaultEmbedDir = "terraform"
+
+// Options configures Generate.
+type Options struct {
+	// Module is the path of the Terraform module, or a go-getter address it
+	// is downloaded from.
+	Module string
+
+	// PackageName is the name of the generated package.
+	PackageName string
+
+	// EmbedDir is the directory of the generated package holding a copy of
+	// the module, which the package embeds. Defaults to DefaultEmbedDir.
+	EmbedDir string
+
+	// OutputDir, when set, is the directory Generate writes the generated
+	// files to. Otherwise they are only returned.
+	OutputDir string
+
+	// AnyType is the Go type generated for values of Terraform's any type.
+	AnyType AnyType
+
+	// Tags are the formats supported besides JSON, as with WithTags.
+	Tags []TagFormat
+
+	// ProtoGoPackage, when set, also generates a .proto file in the protobuf
+	// package ProtoPackage, which defaults to PackageName, and conversions to
+	// the types protoc-gen-go generates from it into the Go package with this
+	// import path. See WithProto.
+	ProtoPackage   string
+	ProtoGoPackage string
+
+	// CLIImportPath, when set, also generates a command in cmd/<package>
+	// that runs the module, given the import path of the generated package.
+	// See WithCLI.
+	CLIImportPath string
+}
+
+// File is a file of the generated package.
+type File struct {
+	// Path is the slash-separated path of the file, relative to the
+	// directory of the package.
+	Path string
+
+	Content []byte
+	Mode    fs.FileMode
+}
+
+// Result holds the files generated for a module.
+type Result struct {
+	// Files are the Go source of the package, the files requested by the
+	// options and the copy of the module it embeds, ordered by path.
+	Files []File
+
+	// Diagnostics are the warnings about the module that didn't stop
+	// generation, such as deprecated type syntax.
+	Diagnostics []string
+}
+
+// Generate generates a Go package for the Terraform module of opts and
+// returns its files, which are only written to disk when opts.OutputDir is
+// set. Problems with the module are returned as errors, and warnings as the
+// diagnostics of the Result. ctx cancels the download of a remote module.
+func Generate(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Module == "" {
+		return nil, fmt.Errorf("missing module")
+	}
+	if opts.PackageName == "" {
+		return nil, fmt.Errorf("missing package name")
+	}
+	if opts.EmbedDir == "" {
+		opts.EmbedDir = DefaultEmbedDir
+	}
+	if opts.ProtoGoPackage != "" && opts.ProtoPackage == "" {
+		opts.ProtoPackage = opts.PackageName
+	}
+
+	result := &Result{}
+	o := &options{
+		anyType:        opts.AnyType,
+		tags:           opts.Tags,
+		protoPackage:   opts.ProtoPackage,
+		protoGoPackage: opts.ProtoGoPackage,
+		cliImportPath:  opts.CLIImportPath,
+		warn: func(msg string) {
+			result.Diagnostics = append(result.Diagnostics, msg)
+		},
+	}
+	for _, format := range o.tags {
+		switch format {
+		case TagYAML, TagTOML, TagMapstructure:
+		default:
+			return nil, fmt.Errorf("unsupported struct tag %q", format)
+		}
+	}
+	if o.protoGoPackage != "" && !protoPackagePattern.MatchString(o.protoPackage) {
+		return nil, fmt.Errorf("invalid protobuf package %q", o.protoPackage)
+	}
+
+	dir, err := os.MkdirTemp("", opts.PackageName)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	moduleDir, module, err := loadModule(ctx, opts.Module, dir, o)
+	if err != nil {
+		return nil, err
+	}
+
+	out, fields, err := generatePackage(moduleDir, module, opts.PackageName, opts.EmbedDir, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.addSource(opts.PackageName+".go", out); err != nil {
+		return nil, fmt.Errorf("failed to render module: %v", err)
+	}
+
+	if o.protoGoPackage != "" {
+		protoSrc, conversions, err := generateProto(module, o, opts.PackageName)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, File{Path: opts.PackageName + ".proto", Content: protoSrc, Mode: 0o644})
+		if err := result.addSource(opts.PackageName+"_proto.go", conversions); err != nil {
+			return nil, fmt.Errorf("failed to render protobuf conversions: %v", err)
+		}
+	}
+
+	if o.cliImportPath != "" {
+		cli, err := generateCLI(fields, opts.PackageName, o.cliImportPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := result.addSource(path.Join("cmd", opts.PackageName, "main.go"), cli); err != nil {
+			return nil, fmt.Errorf("failed to render command: %v", err)
+		}
+	}
+
+	// Copy the Terraform module to the go:embed path
+	moduleFiles, err := readModuleFiles(moduleDir, opts.EmbedDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module files: %v", err)
+	}
+	result.Files = append(result.Files, moduleFiles...)
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Path < result.Files[j].Path })
+
+	if opts.OutputDir != "" {
+		if err := result.Write(opts.OutputDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Write writes the files of r to the directory of the package, creating it
+// and its subdirectories as needed.
+func (r *Result) Write(dir string) error {
+	for _, f := range r.Files {
+		name := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create output dir: %v", err)
+		}
+		if err := os.WriteFile(name, f.Content, f.Mode); err != nil {
+			return fmt.Errorf("failed to write %s: %v", f.Path, err)
+		}
+	}
+	return nil
+}
+
+// File returns the generated file with the given slash-separated path, or
+// nil if there is none.
+func (r *Result) File(path string) *File {
+	for i := range r.Files {
+		if r.Files[i].Path == path {
+			return &r.Files[i]
+		}
+	}
+	return nil
+}
+
+// addSource adds the formatted Go source of src as the file at path.
+func (r *Result) addSource(path string, src *j.File) error {
+	var buf bytes.Buffer
+	if err := src.Render(&buf); err != nil {
+		return err
+	}
+	r.Files = append(r.Files, File{Path: path, Content: buf.Bytes(), Mode: 0o644})
+	return nil
+}
+
+// readModuleFiles returns the files of the module in moduleDir, to be
+// embedded from embedDir. The .terraform directory Terraform keeps its
+// providers and modules in is left out.
+func readModuleFiles(moduleDir string, embedDir string) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(moduleDir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(moduleDir, name)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		files = append(files, File{Path: path.Join(embedDir, filepath.ToSlash(rel)), Content: content, Mode: info.Mode().Perm()})
+		return nil
+	})
+	return files, err
+}
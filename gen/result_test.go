@@ -0,0 +1,87 @@
+package gen_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lolabyte/tf2go/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("returns the generated files without writing them", func(t *testing.T) {
+		result, err := gen.Generate(context.Background(), gen.Options{
+			Module:        "../testdata/basic_tf_module",
+			PackageName:   "test_module",
+			CLIImportPath: "example.com/test_module",
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Diagnostics)
+
+		var paths []string
+		for _, f := range result.Files {
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, []string{
+			"cmd/test_module/main.go",
+			"terraform/README.md",
+			"terraform/main.tf",
+			"terraform/output.tf",
+			"terraform/variables.tf",
+			"test_module.go",
+		}, paths)
+
+		src := result.File("test_module.go")
+		if assert.NotNil(t, src) {
+			assert.Contains(t, string(src.Content), "package test_module\n")
+			assert.Contains(t, string(src.Content), "//go:embed terraform/*\n")
+		}
+		assert.Nil(t, result.File("test_module.proto"))
+	})
+
+	t.Run("writes the files to the output directory", func(t *testing.T) {
+		outDir := t.TempDir()
+		result, err := gen.Generate(context.Background(), gen.Options{
+			Module:      "../testdata/basic_tf_module",
+			PackageName: "test_module",
+			EmbedDir:    "tf",
+			OutputDir:   outDir,
+		})
+		assert.NoError(t, err)
+
+		for _, f := range result.Files {
+			b, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(f.Path)))
+			assert.NoError(t, err)
+			assert.Equal(t, f.Content, b)
+		}
+		assert.NotNil(t, result.File("tf/main.tf"))
+	})
+
+	t.Run("returns warnings as diagnostics", func(t *testing.T) {
+		moduleDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(moduleDir, "variables.tf"), []byte("variable \"legacy\" {\n  type = list\n}\n"), 0o644)
+		assert.NoError(t, err)
+
+		result, err := gen.Generate(context.Background(), gen.Options{Module: moduleDir, PackageName: "legacy"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`variable "legacy": bare list type is deprecated, use list(any) instead`}, result.Diagnostics)
+	})
+
+	t.Run("returns errors instead of writing", func(t *testing.T) {
+		outDir := filepath.Join(t.TempDir(), "out")
+		_, err := gen.Generate(context.Background(), gen.Options{
+			Module:      "../testdata/invalid_type_tf_module",
+			PackageName: "test_module",
+			OutputDir:   outDir,
+		})
+		assert.Error(t, err)
+
+		_, err = os.Stat(outDir)
+		assert.True(t, os.IsNotExist(err))
+
+		_, err = gen.Generate(context.Background(), gen.Options{Module: "../testdata/basic_tf_module"})
+		assert.EqualError(t, err, "missing package name")
+	})
+}
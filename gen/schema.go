@@ -0,0 +1,316 @@
+package gen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// SchemaDialect is the JSON Schema dialect of the documents VariablesSchema
+// generates.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, or a subschema within one. The empty
+// Schema accepts any value.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Type is the name of the JSON type of the values, or a []string of the
+	// names of several types, such as a type and "null"
+	Type interface{} `json:"type,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+}
+
+var validationBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "validation"},
+	},
+}
+
+var conditionAttributeSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "condition"},
+	},
+}
+
+// VariablesSchema returns a JSON Schema document describing the JSON
+// encoding of the Variables generated for the module at inputModulePath,
+// which is what DecodeVariables accepts.
+//
+// Each variable is described by its type, description and default, and
+// whether it is required. Variables also accept null unless they are
+// declared with nullable = false. Sensitive variables are marked writeOnly and their
+// defaults left out. Validations that only allow a fixed set of values, as
+// contains(["a", "b"], var.name) or var.name == "a" || var.name == "b" do,
+// become enums.
+func VariablesSchema(inputModulePath string) (*Schema, error) {
+	dir, err := os.MkdirTemp("", "tf2go-schema")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	o := &options{}
+	moduleDir, module, err := loadModule(context.Background(), inputModulePath, dir, o)
+	if err != nil {
+		return nil, err
+	}
+
+	enums, err := loadEnums(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	nullable, err := loadNullable(moduleDir, module)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{
+		Schema:               SchemaDialect,
+		Title:                "Variables",
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	var typeErrors []string
+	for _, v := range module.Variables {
+		t, err := astNodeType(v, o)
+		if err != nil {
+			typeErrors = append(typeErrors, err.Error())
+			continue
+		}
+
+		prop, err := typeSchema(o, t)
+		if err != nil {
+			typeErrors = append(typeErrors, fmt.Sprintf("variable %q: %v", v.Name, err))
+			continue
+		}
+
+		if typ, ok := prop.Type.(string); ok && nullable[v.Name] {
+			prop.Type = []string{typ, "null"}
+		}
+		prop.Description = v.Description
+		prop.Enum = enums[v.Name]
+		prop.WriteOnly = v.Sensitive
+		if !v.Required && !v.Sensitive {
+			prop.Default = v.Default
+		}
+		if v.Required {
+			schema.Required = append(schema.Required, v.Name)
+		}
+		schema.Properties[v.Name] = prop
+	}
+
+	if len(typeErrors) > 0 {
+		sort.Strings(typeErrors)
+		return nil, fmt.Errorf("%s", strings.Join(typeErrors, "\n"))
+	}
+
+	sort.Strings(schema.Required)
+	return schema, nil
+}
+
+// typeSchema returns the schema of values of a type expression.
+func typeSchema(o *options, typeExpr ast.Node) (*Schema, error) {
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			return typeSchema(o, s.(*ast.ExpressionStatement).Expression)
+		}
+	case *ast.AnyTypeLiteral:
+		return &Schema{}, nil
+	case *ast.BoolTypeLiteral:
+		return &Schema{Type: "boolean"}, nil
+	case *ast.NumberTypeLiteral:
+		// Variables holds numbers as int64 unless a default is fractional
+		if o.isFloat(node) {
+			return &Schema{Type: "number"}, nil
+		}
+		return &Schema{Type: "integer"}, nil
+	case *ast.StringTypeLiteral:
+		return &Schema{Type: "string"}, nil
+	case *ast.ListTypeLiteral:
+		items, err := typeSchema(o, node.TypeExpression)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case *ast.MapTypeLiteral:
+		values, err := typeSchema(o, node.TypeExpression)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case *ast.ObjectTypeLiteral:
+		schema := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+		for _, kv := range node.ObjectSpec.(*ast.ObjectLiteral).SortedKVPairs() {
+			attr := ast.KeyName(kv.Key)
+			prop, err := typeSchema(o, kv.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", attr, err)
+			}
+			if _, optional := kv.Value.(*ast.OptionalTypeLiteral); !optional {
+				schema.Required = append(schema.Required, attr)
+			}
+			schema.Properties[attr] = prop
+		}
+		return schema, nil
+	case *ast.OptionalTypeLiteral:
+		schema, err := typeSchema(o, node.TypeExpression)
+		if err != nil || node.DefaultValue == nil {
+			return schema, err
+		}
+		schema.Default, err = plainValue(node.DefaultValue)
+		return schema, err
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeExpr.String())
+}
+
+// loadEnums returns the values allowed by the validations of the variables
+// in the module at dir, for the variables whose validations only allow a
+// fixed set of values.
+func loadEnums(dir string) (map[string][]interface{}, error) {
+	enums := make(map[string][]interface{})
+
+	blocks, diags := parseVariableBlocks(dir)
+	for _, block := range blocks {
+		name := block.Labels[0]
+
+		content, _, contentDiags := block.Body.PartialContent(validationBlockSchema)
+		diags = append(diags, contentDiags...)
+
+		for _, validation := range content.Blocks {
+			attrs, _, attrDiags := validation.Body.PartialContent(conditionAttributeSchema)
+			diags = append(diags, attrDiags...)
+
+			attr, ok := attrs.Attributes["condition"]
+			if !ok {
+				continue
+			}
+			if values, ok := enumValues(name, attr.Expr); ok {
+				enums[name] = values
+				break
+			}
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return enums, nil
+}
+
+// enumValues returns the values a validation condition allows the variable
+// name to take, when it only allows a fixed set of literal values.
+func enumValues(name string, expr hcl.Expression) ([]interface{}, bool) {
+	switch expr := expr.(type) {
+	case *hclsyntax.ParenthesesExpr:
+		return enumValues(name, expr.Expression)
+	case *hclsyntax.FunctionCallExpr:
+		if expr.Name != "contains" || len(expr.Args) != 2 || !isVariableReference(name, expr.Args[1]) {
+			return nil, false
+		}
+		list, diags := expr.Args[0].Value(nil)
+		if diags.HasErrors() || !list.IsWhollyKnown() || list.IsNull() || !list.CanIterateElements() {
+			return nil, false
+		}
+
+		var values []interface{}
+		for it := list.ElementIterator(); it.Next(); {
+			_, el := it.Element()
+			value, ok := plainLiteral(el)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, value)
+		}
+		return values, true
+	case *hclsyntax.BinaryOpExpr:
+		switch expr.Op {
+		case hclsyntax.OpLogicalOr:
+			lhs, ok := enumValues(name, expr.LHS)
+			if !ok {
+				return nil, false
+			}
+			rhs, ok := enumValues(name, expr.RHS)
+			if !ok {
+				return nil, false
+			}
+			return append(lhs, rhs...), true
+		case hclsyntax.OpEqual:
+			literal := expr.RHS
+			if !isVariableReference(name, expr.LHS) {
+				if !isVariableReference(name, expr.RHS) {
+					return nil, false
+				}
+				literal = expr.LHS
+			}
+
+			val, diags := literal.Value(nil)
+			if diags.HasErrors() {
+				return nil, false
+			}
+			value, ok := plainLiteral(val)
+			if !ok {
+				return nil, false
+			}
+			return []interface{}{value}, true
+		}
+	}
+
+	return nil, false
+}
+
+// isVariableReference reports whether expr is exactly var.name.
+func isVariableReference(name string, expr hcl.Expression) bool {
+	traversal, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(traversal.Traversal) != 2 || traversal.Traversal.RootName() != "var" {
+		return false
+	}
+	attr, ok := traversal.Traversal[1].(hcl.TraverseAttr)
+	return ok && attr.Name == name
+}
+
+// plainLiteral converts a known primitive value into the Go value
+// encoding/json encodes the same way.
+func plainLiteral(val cty.Value) (interface{}, bool) {
+	if !val.IsWhollyKnown() || val.IsNull() || !val.Type().IsPrimitiveType() {
+		return nil, false
+	}
+
+	b, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, false
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
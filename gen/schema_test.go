@@ -0,0 +1,81 @@
+package gen_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lolabyte/tf2go/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariablesSchema(t *testing.T) {
+	t.Run("returns an error for invalid variable types", func(t *testing.T) {
+		_, err := gen.VariablesSchema("../testdata/invalid_type_tf_module")
+		assert.Error(t, err)
+	})
+
+	schema, err := gen.VariablesSchema("../testdata/basic_tf_module")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, gen.SchemaDialect, schema.Schema)
+	assert.Equal(t, false, schema.AdditionalProperties)
+	assert.Contains(t, schema.Required, "sensitive_string")
+	assert.Contains(t, schema.Required, "untyped")
+	assert.NotContains(t, schema.Required, "region")
+
+	tests := []struct {
+		variable string
+		expected string
+	}{
+		{
+			variable: "number_with_default",
+			expected: `{"type":["integer","null"],"default":99}`,
+		},
+		{
+			variable: "ratio",
+			expected: `{"type":["number","null"],"default":0.5}`,
+		},
+		{
+			variable: "list_of_bool",
+			expected: `{"type":["array","null"],"items":{"type":"boolean"}}`,
+		},
+		{
+			variable: "untyped",
+			expected: `{}`,
+		},
+		{
+			variable: "sensitive_string",
+			expected: `{"type":["string","null"],"writeOnly":true}`,
+		},
+		{
+			variable: "region",
+			expected: `{"type":"string","enum":["eu-west-1","us-east-1"],"default":"eu-west-1"}`,
+		},
+		{
+			variable: "environment",
+			expected: `{"description":"The environment to deploy to","type":["string","null"],"enum":["dev","staging","prod"],"default":"dev"}`,
+		},
+		{
+			variable: "annotations",
+			expected: `{"type":["object","null"],"additionalProperties":{},"default":{"replicas":3}}`,
+		},
+		{
+			variable: "optional_list",
+			expected: `{"type":["object","null"],"properties":{"values":{"type":"array","items":{"type":"integer"},"default":[]}},"additionalProperties":false}`,
+		},
+		{
+			variable: "things",
+			expected: `{"type":["array","null"],"items":{"type":"object","properties":{"foo":{"type":"array","items":{"type":"integer"}}},"required":["foo"],"additionalProperties":false}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.variable, func(t *testing.T) {
+			b, err := json.Marshal(schema.Properties[tt.variable])
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(b))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package gen
+
+import (
+	j "github.com/dave/jennifer/jen"
+)
+
+// generateTFVars generates LoadTFVars and ParseTFVars, which decode HCL and
+// JSON tfvars files into Variables after checking them against the type of
+// every variable, and WriteTFVars, which writes Variables as an HCL tfvars
+// file.
+func generateTFVars(src *j.File, fields []variableField) {
+	types := j.Dict{}
+	for _, f := range fields {
+		types[j.Lit(f.variable.Name)] = j.Lit(f.tfType.String())
+	}
+
+	src.Comment("variableTypes records the Terraform type of each variable, which tfvars files are checked against.")
+	src.Var().Id("variableTypes").Op("=").Map(j.String()).String().Values(types).Line()
+
+	src.Comment("VariableTypes returns the Terraform type of each variable, keyed by name, as taken by")
+	src.Comment("terraform.TFVarsSource and terraform.EnvSource.")
+	src.Func().Id("VariableTypes").Params().Map(j.String()).String().Block(
+		j.Id("types").Op(":=").Make(j.Map(j.String()).String(), j.Len(j.Id("variableTypes"))),
+		j.For(j.List(j.Id("name"), j.Id("t")).Op(":=").Range().Id("variableTypes")).Block(
+			j.Id("types").Index(j.Id("name")).Op("=").Id("t"),
+		),
+		j.Return(j.Id("types")),
+	).Line()
+
+	src.Comment("LoadTFVars reads the variables defined in a .tfvars file, or a .tfvars.json file in JSON syntax.")
+	src.Func().Id("LoadTFVars").Params(
+		j.Id("path").String(),
+	).Parens(j.List(j.Id("Variables"), j.Error())).Block(
+		j.List(j.Id("b"), j.Err()).Op(":=").Qual("os", "ReadFile").Call(j.Id("path")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Id("Variables").Values(), j.Err()),
+		),
+		j.Return(j.Id("parseTFVars").Call(j.Id("b"), j.Id("path"))),
+	).Line()
+
+	src.Comment("ParseTFVars decodes variable definitions in HCL or JSON tfvars syntax. Unknown variables and")
+	src.Comment("values that don't match the type of their variable are errors, and variables defined as null")
+	src.Comment("are set with SetNull.")
+	src.Func().Id("ParseTFVars").Params(
+		j.Id("src").Index().Byte(),
+	).Parens(j.List(j.Id("Variables"), j.Error())).Block(
+		j.Return(j.Id("parseTFVars").Call(j.Id("src"), j.Lit(""))),
+	).Line()
+
+	src.Func().Id("parseTFVars").Params(
+		j.Id("src").Index().Byte(),
+		j.Id("filename").String(),
+	).Parens(j.List(j.Id("Variables"), j.Error())).Block(
+		j.Var().Id("v").Id("Variables"),
+		j.List(j.Id("values"), j.Err()).Op(":=").Qual("github.com/lolabyte/tf2go/terraform", "ParseTFVars").Call(j.Id("src"), j.Id("filename"), j.Id("variableTypes")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Id("v"), j.Err()),
+		).Line(),
+
+		j.List(j.Id("b"), j.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(j.Id("values")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Id("v"), j.Err()),
+		),
+		j.If(j.Err().Op(":=").Qual("encoding/json", "Unmarshal").Call(j.Id("b"), j.Op("&").Id("v")), j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Id("v"), j.Err()),
+		).Line(),
+
+		j.For(j.List(j.Id("name"), j.Id("value")).Op(":=").Range().Id("values")).Block(
+			j.If(j.String().Call(j.Id("value")).Op("!=").Lit("null")).Block(
+				j.Continue(),
+			),
+			j.If(j.Err().Op(":=").Id("v").Dot("SetNull").Call(j.Id("name")), j.Err().Op("!=").Nil()).Block(
+				j.Return(j.Id("v"), j.Err()),
+			),
+		),
+		j.Return(j.Id("v"), j.Id("v").Dot("Validate").Call()),
+	).Line()
+
+	src.Comment("WriteTFVars writes the variables to terraform.tfvars in workingDir, in HCL syntax, and returns")
+	src.Comment("its path.")
+	src.Func().Params(
+		j.Id("v").Id("Variables"),
+	).Id("WriteTFVars").Params(
+		j.Id("workingDir").String(),
+	).Parens(j.List(j.String(), j.Error())).Block(
+		j.List(j.Id("b"), j.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(j.Id("v")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Lit(""), j.Err()),
+		),
+		j.List(j.Id("b"), j.Err()).Op("=").Qual("github.com/lolabyte/tf2go/terraform", "FormatTFVars").Call(j.Id("b")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Lit(""), j.Err()),
+		).Line(),
+
+		j.Id("outfile").Op(":=").Qual("path", "Join").Call(j.Id("workingDir"), j.Lit("terraform.tfvars")),
+		j.Err().Op("=").Qual("os", "WriteFile").Call(j.Id("outfile"), j.Id("b"), j.Qual("os", "ModePerm")),
+		j.If(j.Err().Op("!=").Nil()).Block(
+			j.Return(j.Lit(""), j.Qual("fmt", "Errorf").Call(j.Lit("failed to write terraform.tfvars: %v"), j.Err())),
+		).Line(),
+
+		j.Return(j.Id("outfile"), j.Nil()),
+	).Line()
+}
@@ -0,0 +1,339 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	j "github.com/dave/jennifer/jen"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	tfLexer "github.com/lolabyte/tf2go/terraform/lexer"
+	tfParser "github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/lolabyte/tf2go/utils"
+)
+
+// astNodeDefault parses the default value of a variable. tfconfig decodes
+// defaults through their JSON encoding, which is also valid HCL literal
+// syntax, so it is parsed back the same way as any other literal value.
+func astNodeDefault(v *tfconfig.Variable) (ast.Expression, error) {
+	b, err := json.Marshal(v.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default for variable %q: %v", v.Name, err)
+	}
+
+	parser := tfParser.New(tfLexer.New(string(b)))
+	value := parser.ParseValue()
+	if errs := parser.Errors(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid default for variable %q: %s", v.Name, strings.Join(errs, "; "))
+	}
+
+	return value, nil
+}
+
+// markFractionalNumbers records the number types of typeExpr holding a
+// fractional value, either in value, the default value of the variable, or in
+// the default value of an optional attribute, so that they are generated as
+// float64. Numbers are otherwise generated as int64.
+func (o *options) markFractionalNumbers(typeExpr ast.Node, value ast.Expression) {
+	if o == nil {
+		return
+	}
+
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			o.markFractionalNumbers(s.(*ast.ExpressionStatement).Expression, value)
+		}
+	case *ast.NumberTypeLiteral:
+		num, ok := value.(*ast.NumberLiteral)
+		if !ok || num.Value == nil || num.Value.IsInt() {
+			return
+		}
+		if o.fractional == nil {
+			o.fractional = make(map[*ast.NumberTypeLiteral]bool)
+		}
+		o.fractional[node] = true
+	case *ast.ListTypeLiteral:
+		o.markFractionalNumbers(node.TypeExpression, nil)
+		if list, ok := value.(*ast.ListLiteral); ok {
+			for _, el := range list.Elements {
+				o.markFractionalNumbers(node.TypeExpression, el)
+			}
+		}
+	case *ast.MapTypeLiteral:
+		o.markFractionalNumbers(node.TypeExpression, nil)
+		if obj, ok := value.(*ast.ObjectLiteral); ok {
+			for _, v := range obj.KVPairs {
+				o.markFractionalNumbers(node.TypeExpression, v)
+			}
+		}
+	case *ast.ObjectTypeLiteral:
+		values := make(map[string]ast.Expression)
+		if obj, ok := value.(*ast.ObjectLiteral); ok {
+			for k, v := range obj.KVPairs {
+				values[ast.KeyName(k)] = v
+			}
+		}
+		spec, _ := node.ObjectSpec.(*ast.ObjectLiteral)
+		if spec == nil {
+			return
+		}
+		for k, attrType := range spec.KVPairs {
+			o.markFractionalNumbers(attrType, values[ast.KeyName(k)])
+		}
+	case *ast.OptionalTypeLiteral:
+		o.markFractionalNumbers(node.TypeExpression, value)
+		if node.DefaultValue != nil {
+			o.markFractionalNumbers(node.TypeExpression, node.DefaultValue)
+		}
+	}
+}
+
+// goValue converts a literal value into a Go expression of the type eval
+// generates for typeExpr, such as a composite literal for a list or object.
+// name is used to name object structs, as in eval.
+func goValue(opts *options, typeExpr ast.Node, value ast.Expression, name string) (j.Code, error) {
+	if _, ok := value.(*ast.NullLiteral); ok {
+		return zeroValue(typeExpr), nil
+	}
+
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			return goValue(opts, s.(*ast.ExpressionStatement).Expression, value, name)
+		}
+	case *ast.AnyTypeLiteral:
+		if opts.anyType == AnyDynamic {
+			return goJSONValue(value)
+		}
+		return goDynamicValue(value)
+	case *ast.BoolTypeLiteral:
+		b, ok := value.(*ast.Bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool, got %s", value.String())
+		}
+		return j.Id("ptr").Call(j.Lit(b.Value)), nil
+	case *ast.NumberTypeLiteral:
+		num, ok := value.(*ast.NumberLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %s", value.String())
+		}
+		if opts.isFloat(node) {
+			f, _ := num.Value.Float64()
+			return j.Lit(f), nil
+		}
+		n, ok := num.Int64()
+		if !ok {
+			return nil, fmt.Errorf("%s is not an integer", num.String())
+		}
+		return j.Lit(int(n)), nil
+	case *ast.StringTypeLiteral:
+		s, ok := value.(*ast.StringLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %s", value.String())
+		}
+		return j.Lit(s.Value), nil
+	case *ast.ListTypeLiteral:
+		list, ok := value.(*ast.ListLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected a list, got %s", value.String())
+		}
+
+		elements := make([]j.Code, 0, len(list.Elements))
+		for _, el := range list.Elements {
+			code, err := goValue(opts, node.TypeExpression, el, name)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, code)
+		}
+		return goType(opts, node, name).Values(elements...), nil
+	case *ast.MapTypeLiteral:
+		obj, ok := value.(*ast.ObjectLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected a map, got %s", value.String())
+		}
+
+		values := j.Dict{}
+		for _, kv := range obj.SortedKVPairs() {
+			code, err := goValue(opts, node.TypeExpression, kv.Value, name)
+			if err != nil {
+				return nil, err
+			}
+			values[j.Lit(ast.KeyName(kv.Key))] = code
+		}
+		return goType(opts, node, name).Values(values), nil
+	case *ast.ObjectTypeLiteral:
+		obj, ok := value.(*ast.ObjectLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected an object, got %s", value.String())
+		}
+
+		attributes := make(map[string]ast.Expression)
+		for _, kv := range node.ObjectSpec.(*ast.ObjectLiteral).SortedKVPairs() {
+			attributes[ast.KeyName(kv.Key)] = kv.Value
+		}
+
+		values := make(map[string]ast.Expression)
+		for _, kv := range obj.SortedKVPairs() {
+			attr := ast.KeyName(kv.Key)
+			if _, ok := attributes[attr]; !ok {
+				return nil, fmt.Errorf("unknown attribute %q", attr)
+			}
+			values[attr] = kv.Value
+		}
+
+		fields := j.Dict{}
+		for attr, attrType := range attributes {
+			value := values[attr]
+			if _, ok := value.(*ast.NullLiteral); ok || value == nil {
+				// Like Terraform, an optional attribute that is omitted or
+				// null takes its default value
+				opt, ok := attrType.(*ast.OptionalTypeLiteral)
+				if !ok || opt.DefaultValue == nil {
+					continue
+				}
+				value = opt.DefaultValue
+			}
+
+			code, err := goValue(opts, attrType, value, attr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", attr, err)
+			}
+			fields[j.Id(utils.SnakeToCamel(attr))] = code
+		}
+		return j.Op("&").Id(utils.SnakeToCamel(name)).Values(fields), nil
+	case *ast.OptionalTypeLiteral:
+		code, err := goValue(opts, node.TypeExpression, value, name)
+		if err != nil || isNilableType(node.TypeExpression) {
+			return code, err
+		}
+		return j.Id("ptr").Types(goType(opts, node.TypeExpression, name)).Call(code), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", typeExpr.String())
+}
+
+// goDynamicValue converts a literal value into a Go expression of the type
+// encoding/json would decode it to as an interface{}.
+func goDynamicValue(value ast.Expression) (j.Code, error) {
+	switch value := value.(type) {
+	case *ast.NullLiteral:
+		return j.Nil(), nil
+	case *ast.Bool:
+		return j.Lit(value.Value), nil
+	case *ast.NumberLiteral:
+		f, _ := value.Value.Float64()
+		return j.Lit(f), nil
+	case *ast.StringLiteral:
+		return j.Lit(value.Value), nil
+	case *ast.ListLiteral:
+		elements := make([]j.Code, 0, len(value.Elements))
+		for _, el := range value.Elements {
+			code, err := goDynamicValue(el)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, code)
+		}
+		return j.Index().Interface().Values(elements...), nil
+	case *ast.ObjectLiteral:
+		values := j.Dict{}
+		for _, kv := range value.SortedKVPairs() {
+			code, err := goDynamicValue(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+			values[j.Lit(ast.KeyName(kv.Key))] = code
+		}
+		return j.Map(j.String()).Interface().Values(values), nil
+	}
+
+	return nil, fmt.Errorf("unsupported value %s", value.String())
+}
+
+// goJSONValue converts a literal value into a terraform.Dynamic holding its
+// JSON encoding.
+func goJSONValue(value ast.Expression) (j.Code, error) {
+	plain, err := plainValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Qual("github.com/lolabyte/tf2go/terraform", "Dynamic").Call(j.Lit(string(b))), nil
+}
+
+// plainValue converts a literal value into the Go value encoding/json encodes
+// the same way.
+func plainValue(value ast.Expression) (interface{}, error) {
+	switch value := value.(type) {
+	case *ast.NullLiteral:
+		return nil, nil
+	case *ast.Bool:
+		return value.Value, nil
+	case *ast.NumberLiteral:
+		return json.Number(value.Value.Text('f', -1)), nil
+	case *ast.StringLiteral:
+		return value.Value, nil
+	case *ast.ListLiteral:
+		elements := make([]interface{}, 0, len(value.Elements))
+		for _, el := range value.Elements {
+			plain, err := plainValue(el)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, plain)
+		}
+		return elements, nil
+	case *ast.ObjectLiteral:
+		attrs := make(map[string]interface{}, len(value.KVPairs))
+		for k, v := range value.KVPairs {
+			plain, err := plainValue(v)
+			if err != nil {
+				return nil, err
+			}
+			attrs[ast.KeyName(k)] = plain
+		}
+		return attrs, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value %s", value.String())
+}
+
+// goType returns the Go type eval generates for typeExpr, without declaring
+// any structs.
+func goType(opts *options, typeExpr ast.Node, name string) *j.Statement {
+	return eval(nil, opts, typeExpr, &j.Statement{}, name)
+}
+
+// zeroValue returns the Go zero value of the type eval generates for
+// typeExpr, which is how null is represented.
+func zeroValue(typeExpr ast.Node) j.Code {
+	switch node := typeExpr.(type) {
+	case *ast.Type:
+		for _, s := range node.Statements {
+			return zeroValue(s.(*ast.ExpressionStatement).Expression)
+		}
+	case *ast.NumberTypeLiteral:
+		return j.Lit(0)
+	case *ast.StringTypeLiteral:
+		return j.Lit("")
+	}
+	return j.Nil()
+}
+
+// isNilableType reports whether eval generates a type that can be nil for
+// typeExpr, so that it needs no extra pointer when optional.
+func isNilableType(typeExpr ast.Expression) bool {
+	switch typeExpr.(type) {
+	case *ast.AnyTypeLiteral, *ast.BoolTypeLiteral, *ast.ListTypeLiteral, *ast.MapTypeLiteral, *ast.ObjectTypeLiteral:
+		return true
+	}
+	return false
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/lolabyte/tf2go/gen"
+	"github.com/lolabyte/tf2go/terraform/conformance"
 )
 
 var (
@@ -11,21 +16,176 @@ var (
 	outputEmbedDir    string
 	outputPackageName string
 	outputDir         string
+	checkConformance  bool
+	anyType           string
+	tags              string
+	protoPackage      string
+	protoGoPackage    string
+	cliImportPath     string
 )
 
-const defaultOutputEmbedDir = "terraform"
-
 func init() {
 	flag.StringVar(&inputModulePath, "module", "", "path to a TF module")
-	flag.StringVar(&outputEmbedDir, "embed", defaultOutputEmbedDir, "path of the go:embed dir")
+	flag.StringVar(&outputEmbedDir, "embed", gen.DefaultEmbedDir, "path of the go:embed dir")
 	flag.StringVar(&outputPackageName, "package", "", "name of the package to generate")
 	flag.StringVar(&outputDir, "out", "", "path to output directory (will create if not exists)")
-	flag.Parse()
+	flag.StringVar(&anyType, "any", "interface", "Go type generated for the any type: interface (interface{}) or dynamic (terraform.Dynamic)")
+	flag.StringVar(&tags, "tags", "", "comma-separated formats to support besides json: yaml and toml file helpers, mapstructure tags")
+	flag.StringVar(&protoGoPackage, "proto-go-package", "", "also generate a .proto file and conversions to the Go types protoc-gen-go generates into this import path")
+	flag.StringVar(&protoPackage, "proto-package", "", "protobuf package of the generated .proto file (default the -package name)")
+	flag.StringVar(&cliImportPath, "cli", "", "also generate a command running the module in cmd/<package>, given the import path of the generated package")
+	flag.BoolVar(&checkConformance, "conformance", false, "check the module's variable types against HCL's type parser instead of generating")
 }
 
 func main() {
-	err := gen.GenerateTFModulePackage(inputModulePath, outputDir, outputPackageName, outputEmbedDir)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "schema":
+			runSchema(os.Args[2:])
+			return
+		case "docs":
+			runDocs(os.Args[2:])
+			return
+		case "go2tf":
+			runGo2TF(os.Args[2:])
+			return
+		}
+	}
+
+	flag.Parse()
+
+	if checkConformance {
+		runConformance()
+		return
+	}
+
+	opts := anyTypeOptions()
+
+	if tags != "" {
+		var formats []gen.TagFormat
+		for _, tag := range strings.Split(tags, ",") {
+			formats = append(formats, gen.TagFormat(strings.TrimSpace(tag)))
+		}
+		opts = append(opts, gen.WithTags(formats...))
+	}
+
+	if protoGoPackage != "" {
+		if protoPackage == "" {
+			protoPackage = outputPackageName
+		}
+		opts = append(opts, gen.WithProto(protoPackage, protoGoPackage))
+	}
+
+	if cliImportPath != "" {
+		opts = append(opts, gen.WithCLI(cliImportPath))
+	}
+
+	err := gen.GenerateTFModulePackage(inputModulePath, outputDir, outputPackageName, outputEmbedDir, opts...)
 	if err != nil {
 		panic(err)
 	}
 }
+
+func runConformance() {
+	mismatches, err := conformance.CheckModule(inputModulePath)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, m := range mismatches {
+		fmt.Println(m)
+	}
+
+	if len(mismatches) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runSchema implements tf2go schema, which writes the JSON Schema of a
+// module's Variables.
+func runSchema(args []string) {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	fs.StringVar(&inputModulePath, "module", "", "path to a TF module")
+	out := fs.String("out", "", "path of the schema file to write (default stdout)")
+	fs.Parse(args)
+
+	schema, err := gen.VariablesSchema(inputModulePath)
+	if err != nil {
+		panic(err)
+	}
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	b = append(b, '\n')
+
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := os.WriteFile(*out, b, 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// anyTypeOptions returns the options generating the type given by -any for
+// the any type, exiting when it is neither interface nor dynamic.
+func anyTypeOptions() []gen.Option {
+	switch anyType {
+	case "interface":
+		return nil
+	case "dynamic":
+		return []gen.Option{gen.WithAnyType(gen.AnyDynamic)}
+	}
+	fmt.Fprintf(os.Stderr, "invalid -any %q, must be interface or dynamic\n", anyType)
+	os.Exit(2)
+	return nil
+}
+
+// runDocs implements tf2go docs, which writes a Markdown reference for the
+// package generated from a module.
+func runDocs(args []string) {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	fs.StringVar(&inputModulePath, "module", "", "path to a TF module")
+	fs.StringVar(&outputPackageName, "package", "", "name of the generated package")
+	fs.StringVar(&anyType, "any", "interface", "Go type generated for the any type: interface (interface{}) or dynamic (terraform.Dynamic)")
+	out := fs.String("out", "", "path of the Markdown file to write (default stdout)")
+	fs.Parse(args)
+
+	b, err := gen.GenerateDocs(inputModulePath, outputPackageName, anyTypeOptions()...)
+	if err != nil {
+		panic(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := os.WriteFile(*out, b, 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// runGo2TF implements tf2go go2tf, which writes a variables.tf declaring the
+// fields of a Go struct type as variables.
+func runGo2TF(args []string) {
+	fs := flag.NewFlagSet("go2tf", flag.ExitOnError)
+	pkg := fs.String("package", ".", "import path or directory of the Go package declaring the type")
+	typeName := fs.String("type", "", "name of the struct type to declare variables for")
+	out := fs.String("out", "", "path of the variables.tf file to write (default stdout)")
+	fs.Parse(args)
+
+	b, err := gen.GenerateVariablesTF(*pkg, *typeName)
+	if err != nil {
+		panic(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := os.WriteFile(*out, b, 0o644); err != nil {
+		panic(err)
+	}
+}
@@ -3,6 +3,8 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/lolabyte/tf2go/terraform/token"
@@ -58,14 +60,24 @@ func (b *Bool) TokenLiteral() string { return b.Token.Literal }
 func (b *Bool) String() string       { return b.Token.Literal }
 
 type NumberLiteral struct {
-	Token token.Token // token.NUMBER
-	Value int64
+	Token token.Token // token.NUMBER, with a leading "-" for negative numbers
+	Value *big.Float
 }
 
 func (nl *NumberLiteral) expressionNode()      {}
 func (nl *NumberLiteral) TokenLiteral() string { return nl.Token.Literal }
 func (nl *NumberLiteral) String() string       { return nl.Token.Literal }
 
+// Int64 returns the value of the number as an int64, and whether it could be
+// represented exactly.
+func (nl *NumberLiteral) Int64() (int64, bool) {
+	if nl.Value == nil || !nl.Value.IsInt() {
+		return 0, false
+	}
+	n, accuracy := nl.Value.Int64()
+	return n, accuracy == big.Exact
+}
+
 type StringLiteral struct {
 	Token token.Token // token.STR
 	Value string
@@ -73,7 +85,7 @@ type StringLiteral struct {
 
 func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
-func (sl *StringLiteral) String() string       { return sl.Token.Literal }
+func (sl *StringLiteral) String() string       { return Quote(sl.Value) }
 
 type ListLiteral struct {
 	Token    token.Token // token.RIGHT_SQUARE_BRACE
@@ -86,8 +98,8 @@ func (ll *ListLiteral) String() string {
 	var out bytes.Buffer
 
 	elements := make([]string, 0, len(ll.Elements))
-	for i, el := range ll.Elements {
-		elements[i] = el.String()
+	for _, el := range ll.Elements {
+		elements = append(elements, expressionString(el))
 	}
 
 	out.WriteString("[")
@@ -97,25 +109,12 @@ func (ll *ListLiteral) String() string {
 	return out.String()
 }
 
+// TupleLiteral is a list of element types, as given to the tuple type
+// constructor. It has the same syntax as a ListLiteral.
 type TupleLiteral struct {
 	ListLiteral
 }
 
-func (tl *TupleLiteral) String() string {
-	var out bytes.Buffer
-
-	elements := make([]string, 0, len(tl.Elements))
-	for i, exp := range tl.Elements {
-		elements[i] = exp.String()
-	}
-
-	out.WriteString("(")
-	out.WriteString(strings.Join(elements, ", "))
-	out.WriteString(")")
-
-	return out.String()
-}
-
 type ObjectLiteral struct {
 	Token   token.Token // token.LEFT_CURLY_BRACE
 	KVPairs map[Expression]Expression
@@ -127,8 +126,8 @@ func (ol *ObjectLiteral) String() string {
 	var out bytes.Buffer
 
 	elements := make([]string, 0, len(ol.KVPairs))
-	for k, v := range ol.KVPairs {
-		elements = append(elements, fmt.Sprintf("%s = %s", k.String(), v.String()))
+	for _, kv := range ol.SortedKVPairs() {
+		elements = append(elements, fmt.Sprintf("%s = %s", expressionString(kv.Key), expressionString(kv.Value)))
 	}
 
 	out.WriteString("{")
@@ -138,6 +137,65 @@ func (ol *ObjectLiteral) String() string {
 	return out.String()
 }
 
+// KVPair is a single key/value pair of an ObjectLiteral.
+type KVPair struct {
+	Key   Expression
+	Value Expression
+}
+
+// SortedKVPairs returns the key/value pairs of the object ordered by key,
+// giving a stable order to iterate over them in. Duplicate keys are ordered
+// by their position in the input.
+func (ol *ObjectLiteral) SortedKVPairs() []KVPair {
+	pairs := make([]KVPair, 0, len(ol.KVPairs))
+	for k, v := range ol.KVPairs {
+		pairs = append(pairs, KVPair{k, v})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		ki, kj := KeyName(pairs[i].Key), KeyName(pairs[j].Key)
+		if ki != kj {
+			return ki < kj
+		}
+		ti, tj := keyToken(pairs[i].Key), keyToken(pairs[j].Key)
+		if ti.Line != tj.Line {
+			return ti.Line < tj.Line
+		}
+		return ti.Column < tj.Column
+	})
+
+	return pairs
+}
+
+// keyToken returns the token of an object key.
+func keyToken(key Expression) token.Token {
+	switch key := key.(type) {
+	case *Identifier:
+		return key.Token
+	case *StringLiteral:
+		return key.Token
+	}
+	return token.Token{}
+}
+
+// KeyName returns the name of an object key, without the quotes of a quoted
+// key.
+func KeyName(key Expression) string {
+	if sl, ok := key.(*StringLiteral); ok {
+		return sl.Value
+	}
+	return key.String()
+}
+
+// BadExpression is a placeholder for an expression that couldn't be parsed.
+type BadExpression struct {
+	Token token.Token // the first token of the expression
+}
+
+func (be *BadExpression) expressionNode()      {}
+func (be *BadExpression) TokenLiteral() string { return be.Token.Literal }
+func (be *BadExpression) String() string       { return "<bad expression>" }
+
 type NullLiteral struct {
 	Token token.Token // token.NULL
 }
@@ -178,6 +236,7 @@ type OptionalTypeLiteral struct {
 	Token          token.Token // token.OPTIONAL
 	TypeExpression Expression  // may be any Type Keyword token (e.g. token.LIST, token.NUMBER)
 	DefaultValue   Expression
+	ExtraArguments []Expression // arguments beyond the default value, which are invalid
 }
 
 func (os *OptionalTypeLiteral) expressionNode()      {}
@@ -187,7 +246,7 @@ func (os *OptionalTypeLiteral) String() string {
 
 	out.WriteString(os.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(os.TypeExpression.String())
+	out.WriteString(expressionString(os.TypeExpression))
 
 	if os.DefaultValue != nil {
 		out.WriteString(", ")
@@ -234,6 +293,7 @@ func (st *StringTypeLiteral) String() string       { return st.Token.Literal }
 type ListTypeLiteral struct {
 	Token          token.Token // token.LIST
 	TypeExpression Expression
+	ExtraArguments []Expression // arguments beyond the element type, which are invalid
 }
 
 func (lt *ListTypeLiteral) expressionNode()      {}
@@ -243,15 +303,16 @@ func (lt *ListTypeLiteral) String() string {
 
 	out.WriteString(lt.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(lt.TypeExpression.String())
+	out.WriteString(expressionString(lt.TypeExpression))
 	out.WriteString(")")
 
 	return out.String()
 }
 
 type ObjectTypeLiteral struct {
-	Token      token.Token // token.OBJECT
-	ObjectSpec Expression
+	Token          token.Token // token.OBJECT
+	ObjectSpec     Expression
+	ExtraArguments []Expression // arguments beyond the object spec, which are invalid
 }
 
 func (ot *ObjectTypeLiteral) expressionNode()      {}
@@ -261,15 +322,16 @@ func (ot *ObjectTypeLiteral) String() string {
 
 	out.WriteString(ot.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(ot.ObjectSpec.String())
+	out.WriteString(expressionString(ot.ObjectSpec))
 	out.WriteString(")")
 
 	return out.String()
 }
 
 type MapTypeLiteral struct {
-	Token          token.Token
+	Token          token.Token // token.MAP
 	TypeExpression Expression
+	ExtraArguments []Expression // arguments beyond the element type, which are invalid
 }
 
 func (mt *MapTypeLiteral) expressionNode()      {}
@@ -279,8 +341,50 @@ func (mt *MapTypeLiteral) String() string {
 
 	out.WriteString(mt.TokenLiteral())
 	out.WriteString("(")
-	out.WriteString(mt.TypeExpression.String())
+	out.WriteString(expressionString(mt.TypeExpression))
 	out.WriteString(")")
 
 	return out.String()
 }
+
+// expressionString returns the string of exp, or an empty string for the
+// missing argument of a type constructor left by error recovery (e.g. the
+// element type of "list()").
+func expressionString(exp Expression) string {
+	if exp == nil {
+		return ""
+	}
+	return exp.String()
+}
+
+// Quote returns s as a double-quoted HCL string, escaping the characters
+// that would otherwise end the string or start a template sequence.
+func Quote(s string) string {
+	var out strings.Builder
+
+	out.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '$', '%':
+			out.WriteByte(c)
+			if i+1 < len(s) && s[i+1] == '{' {
+				out.WriteByte(c)
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+	out.WriteByte('"')
+
+	return out.String()
+}
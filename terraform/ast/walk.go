@@ -0,0 +1,107 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order: It starts by calling
+// v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor
+// w for each of the non-nil children of node, followed by a call of
+// w.Visit(nil).
+//
+// The key/value pairs of an ObjectLiteral are visited in key order, each
+// key followed by its value.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Type:
+		for _, s := range n.Statements {
+			walk(v, s)
+		}
+
+	case *ExpressionStatement:
+		walk(v, n.Expression)
+
+	case *KeyValueStatement:
+		walk(v, n.Name)
+		walk(v, n.Value)
+
+	case *BadExpression, *Identifier, *Bool, *NumberLiteral, *StringLiteral, *NullLiteral,
+		*AnyTypeLiteral, *BoolTypeLiteral, *NumberTypeLiteral, *StringTypeLiteral:
+		// nothing to do
+
+	case *ListLiteral:
+		walkList(v, n.Elements)
+
+	case *TupleLiteral:
+		walkList(v, n.Elements)
+
+	case *ObjectLiteral:
+		for _, kv := range n.SortedKVPairs() {
+			walk(v, kv.Key)
+			walk(v, kv.Value)
+		}
+
+	case *ListTypeLiteral:
+		walk(v, n.TypeExpression)
+		walkList(v, n.ExtraArguments)
+
+	case *MapTypeLiteral:
+		walk(v, n.TypeExpression)
+		walkList(v, n.ExtraArguments)
+
+	case *ObjectTypeLiteral:
+		walk(v, n.ObjectSpec)
+		walkList(v, n.ExtraArguments)
+
+	case *OptionalTypeLiteral:
+		walk(v, n.TypeExpression)
+		walk(v, n.DefaultValue)
+		walkList(v, n.ExtraArguments)
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+// walk calls Walk for node unless it is nil. Children are interface values,
+// so a missing child is a nil interface rather than a nil pointer.
+func walk(v Visitor, node Node) {
+	if node != nil {
+		Walk(v, node)
+	}
+}
+
+func walkList[N Node](v Visitor, list []N) {
+	for _, node := range list {
+		walk(v, node)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling
+// f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the non-nil children of node, followed by a
+// call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
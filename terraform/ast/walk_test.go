@@ -0,0 +1,106 @@
+package ast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/lexer"
+	"github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, input string) *ast.Type {
+	p := parser.New(lexer.New(input))
+	typeDef := p.ParseType()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return typeDef
+}
+
+func TestInspect(t *testing.T) {
+	typeDef := parse(t, `list(object({ name = string, ports = optional(list(number), [80]), tags = map(any) }))`)
+
+	var visited []string
+	ast.Inspect(typeDef, func(n ast.Node) bool {
+		if n != nil {
+			visited = append(visited, fmt.Sprintf("%T", n))
+		}
+		return true
+	})
+
+	expected := []string{
+		"*ast.Type",
+		"*ast.ExpressionStatement",
+		"*ast.ListTypeLiteral",
+		"*ast.ObjectTypeLiteral",
+		"*ast.ObjectLiteral",
+		"*ast.Identifier", // name
+		"*ast.StringTypeLiteral",
+		"*ast.Identifier", // ports
+		"*ast.OptionalTypeLiteral",
+		"*ast.ListTypeLiteral",
+		"*ast.NumberTypeLiteral",
+		"*ast.ListLiteral",
+		"*ast.NumberLiteral",
+		"*ast.Identifier", // tags
+		"*ast.MapTypeLiteral",
+		"*ast.AnyTypeLiteral",
+	}
+	assert.Equal(t, expected, visited)
+}
+
+func TestInspectPrune(t *testing.T) {
+	typeDef := parse(t, `object({ a = optional(object({ b = string }), {}), c = number })`)
+
+	// Collect the names of top level attributes only
+	var names []string
+	ast.Inspect(typeDef, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Identifier:
+			names = append(names, n.Value)
+		case *ast.OptionalTypeLiteral:
+			return false
+		}
+		return true
+	})
+
+	assert.Equal(t, []string{"a", "c"}, names)
+}
+
+type depthCounter struct {
+	depth    int
+	maxDepth *int
+}
+
+func (d depthCounter) Visit(n ast.Node) ast.Visitor {
+	if n == nil {
+		return nil
+	}
+	if _, ok := n.(*ast.ListTypeLiteral); ok {
+		d.depth++
+		if d.depth > *d.maxDepth {
+			*d.maxDepth = d.depth
+		}
+	}
+	return d
+}
+
+func TestWalk(t *testing.T) {
+	maxDepth := 0
+	ast.Walk(depthCounter{maxDepth: &maxDepth}, parse(t, `list(object({ a = list(list(string)), b = list(bool) }))`))
+	assert.Equal(t, 3, maxDepth)
+}
+
+func TestWalkExtraArguments(t *testing.T) {
+	var count int
+	ast.Inspect(parse(t, `map(string, number, bool)`), func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.StringTypeLiteral, *ast.NumberTypeLiteral, *ast.BoolTypeLiteral:
+			count++
+		}
+		return true
+	})
+	assert.Equal(t, 3, count)
+}
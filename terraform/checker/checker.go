@@ -0,0 +1,162 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+)
+
+var attributeNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+type checker struct {
+	errors []string
+}
+
+// Check validates a parsed type expression against Terraform's type
+// constraint rules, returning a message for every violation found. Messages
+// for nested problems are prefixed with the attribute path they occur at
+// (e.g. "bar.qux[*]").
+func Check(t *ast.Type) []string {
+	c := &checker{errors: []string{}}
+
+	switch len(t.Statements) {
+	case 0:
+		c.errorf("", "missing type expression")
+	case 1:
+	default:
+		c.errorf("", "expected a single type expression, got %d", len(t.Statements))
+	}
+
+	for _, s := range t.Statements {
+		es, ok := s.(*ast.ExpressionStatement)
+		if !ok {
+			c.errorf("", "unexpected statement %s", s.String())
+			continue
+		}
+		c.checkType(es.Expression, "", false)
+	}
+
+	return c.errors
+}
+
+func (c *checker) errorf(path string, format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	if path != "" {
+		msg = fmt.Sprintf("%s: %s", path, msg)
+	}
+	c.errors = append(c.errors, msg)
+}
+
+// checkType checks exp, which must be a type expression. isAttribute is true
+// when exp is the type of an object attribute, the only place optional() is
+// allowed.
+func (c *checker) checkType(exp ast.Expression, path string, isAttribute bool) {
+	switch exp := exp.(type) {
+	case nil:
+		c.errorf(path, "missing type expression")
+	case *ast.BadExpression:
+		// Already reported by the parser
+	case *ast.AnyTypeLiteral, *ast.BoolTypeLiteral, *ast.NumberTypeLiteral, *ast.StringTypeLiteral:
+	case *ast.ListTypeLiteral:
+		c.checkArguments(path, exp.TokenLiteral(), exp.TypeExpression, exp.ExtraArguments)
+		c.checkType(exp.TypeExpression, path+"[*]", false)
+	case *ast.MapTypeLiteral:
+		c.checkArguments(path, exp.TokenLiteral(), exp.TypeExpression, exp.ExtraArguments)
+		c.checkType(exp.TypeExpression, path+"[*]", false)
+	case *ast.ObjectTypeLiteral:
+		c.checkArguments(path, exp.TokenLiteral(), exp.ObjectSpec, exp.ExtraArguments)
+		c.checkObjectSpec(exp.ObjectSpec, path)
+	case *ast.OptionalTypeLiteral:
+		if !isAttribute {
+			c.errorf(path, "optional() may only be used for the attributes of an object type")
+		}
+		if len(exp.ExtraArguments) > 0 {
+			c.errorf(path, "optional() takes at most 2 arguments, got %d", len(exp.ExtraArguments)+2)
+		}
+		c.checkType(exp.TypeExpression, path, false)
+		if isTypeExpression(exp.DefaultValue) {
+			c.errorf(path, "optional() default value must be a literal value, got type %s", exp.DefaultValue.String())
+		}
+	case *ast.Identifier:
+		c.errorf(path, "unknown type keyword %q", exp.Value)
+	default:
+		c.errorf(path, "expected a type, got %s", describe(exp))
+	}
+}
+
+func (c *checker) checkArguments(path string, constructor string, first ast.Expression, extra []ast.Expression) {
+	if first == nil {
+		c.errorf(path, "%s() requires exactly 1 argument, got 0", constructor)
+	} else if len(extra) > 0 {
+		c.errorf(path, "%s() requires exactly 1 argument, got %d", constructor, len(extra)+1)
+	}
+}
+
+func (c *checker) checkObjectSpec(spec ast.Expression, path string) {
+	if spec == nil {
+		return
+	}
+
+	obj, ok := spec.(*ast.ObjectLiteral)
+	if !ok {
+		c.errorf(path, "object() requires a map of attribute names to types, got %s", describe(spec))
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, kv := range obj.SortedKVPairs() {
+		if _, ok := kv.Key.(*ast.BadExpression); ok {
+			continue
+		}
+
+		ident, ok := kv.Key.(*ast.Identifier)
+		if !ok || !attributeNamePattern.MatchString(ident.Value) {
+			c.errorf(path, "invalid attribute name %s, object attribute names must be identifiers", describe(kv.Key))
+			continue
+		}
+
+		// Duplicates are ordered by position, so the later ones are reported
+		if seen[ident.Value] {
+			c.errorf(path, "duplicate attribute name %q", ident.Value)
+			continue
+		}
+		seen[ident.Value] = true
+
+		attrPath := ident.Value
+		if path != "" {
+			attrPath = path + "." + ident.Value
+		}
+
+		c.checkType(kv.Value, attrPath, true)
+	}
+}
+
+func isTypeExpression(exp ast.Expression) bool {
+	switch exp.(type) {
+	case *ast.AnyTypeLiteral, *ast.BoolTypeLiteral, *ast.NumberTypeLiteral, *ast.StringTypeLiteral,
+		*ast.ListTypeLiteral, *ast.MapTypeLiteral, *ast.ObjectTypeLiteral, *ast.OptionalTypeLiteral:
+		return true
+	}
+	return false
+}
+
+func describe(exp ast.Expression) string {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		return fmt.Sprintf("%q", exp.Value)
+	case *ast.StringLiteral:
+		return fmt.Sprintf("string literal %q", exp.Value)
+	case *ast.NumberLiteral:
+		return fmt.Sprintf("number literal %s", exp.TokenLiteral())
+	case *ast.Bool:
+		return fmt.Sprintf("bool literal %s", exp.TokenLiteral())
+	case *ast.NullLiteral:
+		return "null"
+	case *ast.ListLiteral:
+		return "list literal"
+	case *ast.ObjectLiteral:
+		return "object literal"
+	}
+	return exp.String()
+}
@@ -0,0 +1,115 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform/lexer"
+	"github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		title    string
+		input    string
+		expected []string
+	}{
+		{
+			title:    "Scalar type",
+			input:    "string",
+			expected: []string{},
+		},
+		{
+			title: "Optional object attributes",
+			input: `object({
+				name    = string
+				enabled = optional(bool, true)
+				website = optional(object({
+					index_document = optional(string, "index.html")
+				}), {})
+			})`,
+			expected: []string{},
+		},
+		{
+			title:    "Keyword attribute names",
+			input:    `object({ string = number, list = bool, ipv4-cidr = string })`,
+			expected: []string{},
+		},
+		{
+			title:    "Top level optional",
+			input:    "optional(list(number), [])",
+			expected: []string{"optional() may only be used for the attributes of an object type"},
+		},
+		{
+			title: "Optional nested in a collection",
+			input: `object({ foo = list(optional(string)) })`,
+			expected: []string{
+				"foo[*]: optional() may only be used for the attributes of an object type",
+			},
+		},
+		{
+			title:    "Nested optional",
+			input:    `object({ foo = optional(optional(string)) })`,
+			expected: []string{"foo: optional() may only be used for the attributes of an object type"},
+		},
+		{
+			title:    "Optional with too many arguments",
+			input:    `object({ foo = optional(string, "a", "b") })`,
+			expected: []string{"foo: optional() takes at most 2 arguments, got 3"},
+		},
+		{
+			title:    "Optional with a type as the default",
+			input:    `object({ foo = optional(string, number) })`,
+			expected: []string{"foo: optional() default value must be a literal value, got type number"},
+		},
+		{
+			title:    "List without arguments",
+			input:    "list()",
+			expected: []string{"list() requires exactly 1 argument, got 0", "[*]: missing type expression"},
+		},
+		{
+			title:    "Map with too many arguments",
+			input:    "map(string, number)",
+			expected: []string{"map() requires exactly 1 argument, got 2"},
+		},
+		{
+			title:    "Object without an object spec",
+			input:    "object(string)",
+			expected: []string{"object() requires a map of attribute names to types, got string"},
+		},
+		{
+			title:    "Quoted attribute name",
+			input:    `object({ "foo" = string })`,
+			expected: []string{`invalid attribute name string literal "foo", object attribute names must be identifiers`},
+		},
+		{
+			title:    "Duplicate attribute names",
+			input:    `object({ a = string, b = object({ c = bool, c = list(numbr) }), a = number })`,
+			expected: []string{`duplicate attribute name "a"`, `b: duplicate attribute name "c"`},
+		},
+		{
+			title: "Nested errors",
+			input: `object({
+				bar = object({
+					qux = list(object({ bong = numbr }))
+				})
+			})`,
+			expected: []string{`bar.qux[*].bong: unknown type keyword "numbr"`},
+		},
+		{
+			title:    "Literal value",
+			input:    "[1, 2]",
+			expected: []string{"expected a type, got list literal"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.input))
+			typeDef := p.ParseType()
+			assert.Empty(t, p.Errors())
+
+			assert.Equal(t, tc.expected, Check(typeDef))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package conformance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/lolabyte/tf2go/terraform"
+	"github.com/lolabyte/tf2go/terraform/checker"
+	"github.com/lolabyte/tf2go/terraform/lexer"
+	"github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Mismatch describes a type expression that terraform/parser and HCL's
+// typeexpr disagree on. When both types are the same, the disagreement is in
+// the default values of optional attributes.
+type Mismatch struct {
+	Variable string // empty when the expression didn't come from a module
+	TypeExpr string
+
+	ParserType  cty.Type
+	ParserError error
+
+	HCLType  cty.Type
+	HCLError error
+}
+
+func (m Mismatch) String() string {
+	var out strings.Builder
+
+	if m.Variable != "" {
+		out.WriteString(fmt.Sprintf("variable %q: ", m.Variable))
+	}
+	out.WriteString(fmt.Sprintf("%s: parser ", m.TypeExpr))
+	out.WriteString(describe(m.ParserType, m.ParserError))
+	out.WriteString(", hcl ")
+	out.WriteString(describe(m.HCLType, m.HCLError))
+	if m.ParserError == nil && m.HCLError == nil && m.ParserType.Equals(m.HCLType) {
+		out.WriteString(", but with different optional attribute defaults")
+	}
+
+	return out.String()
+}
+
+func describe(ty cty.Type, err error) string {
+	if err != nil {
+		return fmt.Sprintf("failed (%v)", err)
+	}
+	return fmt.Sprintf("returned %s", typeexpr.TypeString(ty))
+}
+
+// CheckModule parses the type of every variable in the Terraform module at
+// dir with both terraform/parser and HCL's typeexpr, returning a Mismatch for
+// each variable where the results differ. Variables without a
+// type are skipped.
+func CheckModule(dir string) ([]Mismatch, error) {
+	module, diags := tfconfig.LoadModule(dir)
+	if diags.HasErrors() {
+		return nil, diags.Err()
+	}
+
+	var names []string
+	for name := range module.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mismatches []Mismatch
+	for _, name := range names {
+		v := module.Variables[name]
+		if v.Type == "" {
+			continue
+		}
+
+		if m := CheckType(v.Type); m != nil {
+			m.Variable = v.Name
+			mismatches = append(mismatches, *m)
+		}
+	}
+
+	return mismatches, nil
+}
+
+// CheckType parses a single type expression with both terraform/parser and
+// HCL's typeexpr and returns a Mismatch if they disagree, or nil if they
+// produce the same type and optional attribute defaults or both reject it.
+func CheckType(typeExpr string) *Mismatch {
+	parserType, parserDefaults, parserErr := parserCtyType(typeExpr)
+	hclType, hclDefaults, hclErr := hclCtyType(typeExpr)
+
+	switch {
+	case parserErr != nil && hclErr != nil:
+		return nil
+	case parserErr == nil && hclErr == nil && parserType.Equals(hclType) && defaultsEqual(parserDefaults, hclDefaults):
+		return nil
+	}
+
+	return &Mismatch{
+		TypeExpr:    typeExpr,
+		ParserType:  parserType,
+		ParserError: parserErr,
+		HCLType:     hclType,
+		HCLError:    hclErr,
+	}
+}
+
+func parserCtyType(typeExpr string) (cty.Type, *typeexpr.Defaults, error) {
+	p := parser.New(lexer.New(typeExpr))
+	t := p.ParseType()
+
+	errs := p.Errors()
+	if len(errs) == 0 {
+		errs = checker.Check(t)
+	}
+	if len(errs) > 0 {
+		return cty.NilType, nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return terraform.CtyType(t)
+}
+
+func hclCtyType(typeExpr string) (ty cty.Type, defaults *typeexpr.Defaults, err error) {
+	// typeexpr panics on some invalid input, such as an optional attribute
+	// declared twice, which is reported as a failure to parse instead
+	defer func() {
+		if r := recover(); r != nil {
+			ty, defaults, err = cty.NilType, nil, fmt.Errorf("typeexpr panicked: %v", r)
+		}
+	}()
+
+	expr, diags := hclsyntax.ParseExpression([]byte(typeExpr), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, nil, diags
+	}
+
+	// Terraform itself accepts the legacy bare list and map keywords
+	switch hcl.ExprAsKeyword(expr) {
+	case "list":
+		return cty.List(cty.DynamicPseudoType), nil, nil
+	case "map":
+		return cty.Map(cty.DynamicPseudoType), nil, nil
+	}
+
+	if err := checkDuplicateAttributes(expr); err != nil {
+		return cty.NilType, nil, err
+	}
+
+	ty, defaults, diags = typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.NilType, nil, diags
+	}
+
+	return ty, defaults, nil
+}
+
+// checkDuplicateAttributes returns an error for an object type in expr that
+// declares an attribute twice, which Terraform rejects but typeexpr accepts,
+// keeping the last one.
+func checkDuplicateAttributes(expr hcl.Expression) error {
+	switch expr := expr.(type) {
+	case *hclsyntax.FunctionCallExpr:
+		for _, arg := range expr.Args {
+			if err := checkDuplicateAttributes(arg); err != nil {
+				return err
+			}
+		}
+	case *hclsyntax.ObjectConsExpr:
+		seen := make(map[string]bool)
+		for _, item := range expr.Items {
+			name := hcl.ExprAsKeyword(item.KeyExpr)
+			if seen[name] && name != "" {
+				return fmt.Errorf("%s: duplicate attribute name %q", item.KeyExpr.Range(), name)
+			}
+			seen[name] = true
+
+			if err := checkDuplicateAttributes(item.ValueExpr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func defaultsEqual(a, b *typeexpr.Defaults) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if !a.Type.Equals(b.Type) || len(a.DefaultValues) != len(b.DefaultValues) || len(a.Children) != len(b.Children) {
+		return false
+	}
+
+	for name, val := range a.DefaultValues {
+		other, ok := b.DefaultValues[name]
+		if !ok || !val.RawEquals(other) {
+			return false
+		}
+	}
+
+	for name, child := range a.Children {
+		if !defaultsEqual(child, b.Children[name]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckModule(t *testing.T) {
+	modules := []string{
+		"../../test_module/terraform",
+		"../../testdata/basic_tf_module",
+		"../../testdata/invalid_type_tf_module",
+	}
+
+	for _, dir := range modules {
+		t.Run(dir, func(t *testing.T) {
+			mismatches, err := CheckModule(dir)
+			assert.NoError(t, err)
+			for _, m := range mismatches {
+				t.Errorf("mismatch: %s", m)
+			}
+		})
+	}
+}
+
+func TestCheckType(t *testing.T) {
+	agree := []string{
+		"any",
+		"bool",
+		"number",
+		"string",
+		"list",
+		"map",
+		"list(string)",
+		"map(list(number))",
+		"list(any)",
+		`object({ name = string, tags = map(string) })`,
+		`object({ string = number, ipv4-cidr = string })`,
+		`object({
+			name    = string
+			enabled = optional(bool, true)
+			website = optional(object({
+				index_document = optional(string, "index.html")
+			}), {})
+		})`,
+		`list(object({ foo = list(number) }))`,
+		`object({ ports = optional(list(number), [80, 443]), tags = optional(map(string), { env = "dev" }) })`,
+		`object({ ratio = optional(number, -0.5), labels = optional(map(string), { "app.kubernetes.io/name": "web" }) })`,
+		"object({\n  script = optional(string, <<-EOT\n    echo hi\n  EOT\n  ) # comment\n})",
+		// Invalid for both
+		"optional(string)",
+		"list()",
+		"map(string, number)",
+		`object({ "foo" = string })`,
+		"strin",
+		`object({ a = string, a = number })`,
+		`object({ a = optional(string, "x"), b = object({ c = optional(number), c = optional(number, 1) }) })`,
+	}
+
+	for _, typeExpr := range agree {
+		t.Run(typeExpr, func(t *testing.T) {
+			if m := CheckType(typeExpr); m != nil {
+				t.Errorf("mismatch: %s", m)
+			}
+		})
+	}
+
+	// Known gaps in terraform/parser. These should move to the list above as
+	// support is added.
+	gaps := []string{
+		"set(string)",
+		"tuple([string, number])",
+	}
+
+	for _, typeExpr := range gaps {
+		t.Run(typeExpr, func(t *testing.T) {
+			m := CheckType(typeExpr)
+			if assert.NotNil(t, m, "gap has been closed, move it to the list of agreeing types") {
+				assert.Error(t, m.ParserError)
+				assert.NoError(t, m.HCLError)
+			}
+		})
+	}
+}
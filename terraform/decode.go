@@ -0,0 +1,272 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DecodeError is a value of decoded input that doesn't match the type of its
+// variable or attribute.
+type DecodeError struct {
+	// Path is the attribute path of the value, e.g. container.bar.qux[2].bong.
+	Path string
+
+	Reason string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+}
+
+// DecodeErrors holds every invalid value of decoded input, ordered by path.
+type DecodeErrors []*DecodeError
+
+func (errs DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// DecodeStrict decodes a JSON object from r into v, which must be a pointer
+// to a generated type such as Variables. Unlike encoding/json, unknown
+// variables and attributes are errors, and every value must match the type of
+// its field exactly: numbers aren't taken from strings, numbers must be whole,
+// though they may be written as 1e3 or 1.0, where the field is an integer,
+// and the input must set the required variables as well as the required
+// attributes of objects. Every invalid value is reported at once, as
+// DecodeErrors, so that the input is never left for encoding/json to reject.
+//
+// Variables set to null are set with SetNull when v has that method, which
+// returns an error for those that can't be null, and the result is checked
+// with its Validate method when it has one.
+func DecodeStrict(r io.Reader, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("terraform.DecodeStrict: expected a pointer to a struct, got %T", v)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var input interface{}
+	if err := dec.Decode(&input); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after the top-level JSON value")
+	}
+
+	attrs, ok := input.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected an object of variables, got %s", jsonKind(input))
+	}
+
+	var errs DecodeErrors
+	checkStruct(attrs, rv.Elem().Type(), "", &errs)
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+		return errs
+	}
+
+	// Integers are rewritten in the form encoding/json decodes
+	b, err = json.Marshal(attrs)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+
+	if nv, ok := v.(interface{ SetNull(name string) error }); ok {
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if attrs[name] != nil {
+				continue
+			}
+			if err := nv.SetNull(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if vv, ok := rv.Elem().Interface().(interface{ Validate() error }); ok {
+		return vv.Validate()
+	}
+	return nil
+}
+
+// checkStruct checks the attributes of an object against the fields of the
+// struct type t, replacing them with their checked values. At the top level,
+// where path is empty, the attributes are variables, which may be null.
+func checkStruct(attrs map[string]interface{}, t reflect.Type, path string, errs *DecodeErrors) {
+	kind := "attribute"
+	if path == "" {
+		kind = "variable"
+	}
+
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() {
+			name, _ := fieldName(field)
+			fields[name] = field
+		}
+	}
+
+	for name, value := range attrs {
+		field, ok := fields[name]
+		if !ok {
+			*errs = append(*errs, &DecodeError{Path: joinPath(path, name), Reason: "unknown " + kind})
+			continue
+		}
+
+		if value == nil && path == "" {
+			continue
+		}
+		attrs[name] = checkValue(value, field.Type, joinPath(path, name), errs)
+	}
+
+	for name, field := range fields {
+		if _, ok := attrs[name]; !ok && hasTagOption(field, "required") {
+			*errs = append(*errs, &DecodeError{Path: joinPath(path, name), Reason: "required " + kind + " is missing"})
+		}
+	}
+}
+
+// checkValue checks a decoded JSON value against the Go type t and returns
+// it, with integers rewritten without a fraction or exponent.
+func checkValue(value interface{}, t reflect.Type, path string, errs *DecodeErrors) interface{} {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, &DecodeError{Path: path, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		default:
+			fail("null is not allowed")
+		}
+		return value
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			fail("expected a bool, got %s", jsonKind(value))
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			fail("expected a string, got %s", jsonKind(value))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("expected a number, got %s", jsonKind(value))
+			break
+		}
+		f, _, err := big.ParseFloat(n.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			fail("%s is out of range", n)
+			break
+		}
+		if !f.IsInt() {
+			fail("%s is not a whole number", n)
+			break
+		}
+		i, _ := f.Int(nil)
+		if !i.IsInt64() || reflect.Zero(t).OverflowInt(i.Int64()) {
+			fail("%s is out of range", n)
+			break
+		}
+		return json.Number(i.String())
+	case reflect.Float32, reflect.Float64:
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("expected a number, got %s", jsonKind(value))
+			break
+		}
+		if _, err := strconv.ParseFloat(n.String(), t.Bits()); err != nil {
+			fail("%s is out of range", n)
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Raw JSON, such as terraform.Dynamic, holds a value of any type
+			break
+		}
+		elements, ok := value.([]interface{})
+		if !ok {
+			fail("expected a list, got %s", jsonKind(value))
+			break
+		}
+		for i, el := range elements {
+			elements[i] = checkValue(el, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		elements, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected a map, got %s", jsonKind(value))
+			break
+		}
+		for key, el := range elements {
+			elements[key] = checkValue(el, t.Elem(), fmt.Sprintf("%s[%q]", path, key), errs)
+		}
+	case reflect.Struct:
+		attrs, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected an object, got %s", jsonKind(value))
+			break
+		}
+		checkStruct(attrs, t, path, errs)
+	default:
+		fail("unsupported type %s", t)
+	}
+	return value
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonKind describes the kind of a decoded JSON value in error messages.
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a bool"
+	case json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", value)
+}
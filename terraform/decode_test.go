@@ -0,0 +1,103 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "valid",
+			input: `{"password": "secret", "region": "eu-west-1", "server": {"name": "web", "ports": [80], "labels": {"tier": "frontend"}}, "extra": [1, "a"], "raw": {"a": null}}`,
+		},
+		{
+			name:  "optional attributes and nulls",
+			input: `{"password": "secret", "server": {"name": "web", "ports": null, "weight": null}, "region": null, "extra": null}`,
+		},
+		{
+			name:  "unknown variables and attributes",
+			input: `{"password": "secret", "zone": "a", "server": {"name": "web", "ports": [], "port": 80}}`,
+			err: `server.port: unknown attribute
+zone: unknown variable`,
+		},
+		{
+			name:  "wrong types",
+			input: `{"region": 1, "password": ["a"], "server": {"name": true, "ports": ["80", 443.5, 99999999999999999999, 1e400], "labels": {"tier": 1}, "weight": {}}}`,
+			err: `password: expected a string, got a list
+region: expected a string, got a number
+server.labels["tier"]: expected a string, got a number
+server.name: expected a string, got a bool
+server.ports[0]: expected a number, got a string
+server.ports[1]: 443.5 is not a whole number
+server.ports[2]: 99999999999999999999 is out of range
+server.ports[3]: 1e400 is out of range
+server.weight: expected a number, got an object`,
+		},
+		{
+			name:  "wrong nesting",
+			input: `{"password": "secret", "server": [{"name": "web"}], "secrets": {"name": "vault", "ports": [1], "labels": ["a"]}}`,
+			err: `secrets.labels: expected a map, got a list
+server: expected an object, got a list`,
+		},
+		{
+			name:  "missing required attributes",
+			input: `{"server": {"weight": 1, "name": null}}`,
+			err: `password: required variable is missing
+server.name: null is not allowed
+server.ports: required attribute is missing`,
+		},
+		{
+			name:  "not an object",
+			input: `[]`,
+			err:   `expected an object of variables, got a list`,
+		},
+		{
+			name:  "trailing data",
+			input: `{} {}`,
+			err:   `unexpected data after the top-level JSON value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v testVariables
+			err := DecodeStrict(strings.NewReader(tt.input), &v)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDecodeStrictNumbers(t *testing.T) {
+	var v testVariables
+	err := DecodeStrict(strings.NewReader(`{"password": "secret", "server": {"name": "web", "ports": [1e3, 80.0, -2E1], "weight": 1.5e1}}`), &v)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1000, 80, -20}, v.Server.Ports)
+	assert.Equal(t, int64(15), *v.Server.Weight)
+}
+
+func TestDecodeStrictSetsNull(t *testing.T) {
+	var v mergeVariables
+	err := DecodeStrict(strings.NewReader(`{"region": null, "server": {"name": "web", "ports": [80]}}`), &v)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"region": true}, v.nulls)
+	assert.Equal(t, &testServer{Name: "web", Ports: []int64{80}}, v.Server)
+
+	err = DecodeStrict(strings.NewReader(`{"tags": null}`), &v)
+	assert.EqualError(t, err, `variable "tags" is not nullable`)
+
+	errs, ok := DecodeStrict(strings.NewReader(`{"ports": [true]}`), &v).(DecodeErrors)
+	if assert.True(t, ok) {
+		assert.Equal(t, DecodeErrors{{Path: "ports[0]", Reason: "expected a number, got a bool"}}, errs)
+	}
+}
@@ -0,0 +1,309 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a difference between two values of a generated type, such as two
+// Variables.
+type Change struct {
+	// Path is the Terraform attribute path of the value that changed, e.g.
+	// server.ports[1] or tags["env"].
+	Path string
+
+	// Old and New are the values before and after the change, where nil
+	// means null or absent. Both are nil for sensitive values.
+	Old, New interface{}
+
+	// Sensitive is true when the value belongs to a sensitive variable or
+	// output, whose values are never reported.
+	Sensitive bool
+}
+
+func (c Change) String() string {
+	if c.Sensitive {
+		return fmt.Sprintf("%s: (sensitive value)", c.Path)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Path, formatValue(c.Old), formatValue(c.New))
+}
+
+func formatValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// NullVariables is implemented by generated Variables, whose variables can be
+// set to null explicitly. A variable set to null is compared as null,
+// whatever the value of its field.
+type NullVariables interface {
+	// NullVariables returns the Terraform names of the variables set to null.
+	NullVariables() []string
+}
+
+// DeepCopy returns a copy of v that shares no memory with it. Unexported
+// struct fields are copied shallowly.
+func DeepCopy[T any](v T) T {
+	in := reflect.ValueOf(&v).Elem()
+	out := reflect.New(in.Type()).Elem()
+	deepCopy(out, in)
+	return out.Interface().(T)
+}
+
+func deepCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.New(src.Type().Elem()))
+		deepCopy(dst.Elem(), src.Elem())
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		for it := src.MapRange(); it.Next(); {
+			val := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(val, it.Value())
+			dst.SetMapIndex(it.Key(), val)
+		}
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		val := reflect.New(src.Elem().Type()).Elem()
+		deepCopy(val, src.Elem())
+		dst.Set(val)
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				deepCopy(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// Equal reports whether a and b hold the same values.
+func Equal[T any](a, b T) bool {
+	return len(Diff(a, b)) == 0
+}
+
+// Diff returns the changes from a to b, ordered by path. Struct fields are
+// named after their tf2go or json struct tag, and the values of fields whose
+// tf2go tag is marked sensitive are redacted. Nil and empty lists and maps are
+// equal, as encoding/json omits both from the variables Terraform is given.
+func Diff[T any](a, b T) []Change {
+	d := &differ{}
+
+	va, vb := reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem()
+	for va.Kind() == reflect.Pointer && !va.IsNil() && !vb.IsNil() {
+		va, vb = va.Elem(), vb.Elem()
+	}
+
+	if va.Kind() == reflect.Struct {
+		d.diffFields(va, vb, "", false, nullSet(a), nullSet(b))
+	} else {
+		d.diff(va, vb, "", false)
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool { return d.changes[i].Path < d.changes[j].Path })
+	return d.changes
+}
+
+func nullSet(v interface{}) map[string]bool {
+	nv, ok := v.(NullVariables)
+	if !ok {
+		return nil
+	}
+
+	nulls := make(map[string]bool)
+	for _, name := range nv.NullVariables() {
+		nulls[name] = true
+	}
+	return nulls
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) report(path string, a, b reflect.Value, sensitive bool) {
+	c := Change{Path: path, Sensitive: sensitive}
+	if !sensitive {
+		c.Old, c.New = plainInterface(a), plainInterface(b)
+	}
+	d.changes = append(d.changes, c)
+}
+
+// plainInterface returns the value held by v, dereferencing pointers, or nil
+// for null.
+func plainInterface(v reflect.Value) interface{} {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func (d *differ) diff(a, b reflect.Value, path string, sensitive bool) {
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil() || b.IsNil() || a.Kind() == reflect.Interface && a.Elem().Type() != b.Elem().Type():
+			d.report(path, a, b, sensitive)
+		default:
+			d.diff(a.Elem(), b.Elem(), path, sensitive)
+		}
+	case reflect.Struct:
+		d.diffFields(a, b, path, sensitive, nil, nil)
+	case reflect.Slice:
+		if a.Type().Elem().Kind() == reflect.Uint8 {
+			// Raw JSON, such as outputs and terraform.Dynamic
+			if !jsonEqual(a.Bytes(), b.Bytes()) {
+				d.report(path, a, b, sensitive)
+			}
+			return
+		}
+
+		if a.Len() != b.Len() {
+			d.report(path, a, b, sensitive)
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			d.diff(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), sensitive)
+		}
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[k.String()] = k
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			elemPath := fmt.Sprintf("%s[%q]", path, name)
+			va, vb := a.MapIndex(keys[name]), b.MapIndex(keys[name])
+			if !va.IsValid() || !vb.IsValid() {
+				d.report(elemPath, va, vb, sensitive)
+				continue
+			}
+			d.diff(va, vb, elemPath, sensitive)
+		}
+	default:
+		if !a.CanInterface() || a.Interface() != b.Interface() {
+			d.report(path, a, b, sensitive)
+		}
+	}
+}
+
+// jsonEqual reports whether a and b are JSON encodings of the same value,
+// whatever their whitespace and order of object keys.
+func jsonEqual(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	va, errA := decodeJSON(a)
+	vb, errB := decodeJSON(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(va, vb)
+}
+
+// decodeJSON decodes a JSON value into plain Go values, keeping numbers as
+// written.
+func decodeJSON(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// diffFields compares the exported fields of two structs. The fields named in
+// nullsA or nullsB are compared as null in a or b respectively.
+func (d *differ) diffFields(a, b reflect.Value, path string, sensitive bool, nullsA, nullsB map[string]bool) {
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, fieldSensitive := fieldName(field)
+		fieldPath := joinPath(path, name)
+		fieldSensitive = fieldSensitive || sensitive
+
+		va, vb := a.Field(i), b.Field(i)
+		if nullsA[name] || nullsB[name] {
+			if nullsA[name] != nullsB[name] {
+				if nullsA[name] {
+					va = reflect.Value{}
+				} else {
+					vb = reflect.Value{}
+				}
+				d.report(fieldPath, va, vb, fieldSensitive)
+			}
+			continue
+		}
+
+		d.diff(va, vb, fieldPath, fieldSensitive)
+	}
+}
+
+// fieldName returns the Terraform name of a struct field from its tf2go or
+// json tag, and whether the tf2go tag marks it as sensitive.
+func fieldName(field reflect.StructField) (string, bool) {
+	if tag, ok := field.Tag.Lookup("tf2go"); ok {
+		name, _, _ := strings.Cut(tag, ",")
+		return name, hasTagOption(field, "sensitive")
+	}
+
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name, false
+		}
+	}
+
+	return field.Name, false
+}
+
+// hasTagOption reports whether the tf2go tag of field has the given option,
+// such as required or sensitive.
+func hasTagOption(field reflect.StructField, option string) bool {
+	tag, ok := field.Tag.Lookup("tf2go")
+	if !ok {
+		return false
+	}
+	for _, o := range strings.Split(tag, ",")[1:] {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
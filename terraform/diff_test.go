@@ -0,0 +1,192 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testServer struct {
+	Name   string            `json:"name,omitempty" tf2go:"name,required"`
+	Ports  []int64           `json:"ports,omitempty" tf2go:"ports,required"`
+	Labels map[string]string `json:"labels,omitempty" tf2go:"labels"`
+	Weight *int64            `json:"weight,omitempty" tf2go:"weight"`
+}
+
+type testVariables struct {
+	Region   string      `json:"region,omitempty" tf2go:"region"`
+	Password string      `json:"password,omitempty" tf2go:"password,required,sensitive"`
+	Server   *testServer `json:"server,omitempty" tf2go:"server"`
+	Secrets  *testServer `json:"secrets,omitempty" tf2go:"secrets,sensitive"`
+	Extra    interface{} `json:"extra,omitempty" tf2go:"extra"`
+	Raw      Dynamic     `json:"raw,omitempty" tf2go:"raw"`
+
+	nulls map[string]bool
+}
+
+func (v testVariables) NullVariables() []string {
+	var names []string
+	for name := range v.nulls {
+		names = append(names, name)
+	}
+	return names
+}
+
+type testOutputs struct {
+	Name   json.RawMessage `json:"name,omitempty"`
+	Secret json.RawMessage `json:"secret,omitempty" tf2go:"secret,sensitive"`
+}
+
+func newTestVariables() testVariables {
+	weight := int64(1)
+	return testVariables{
+		Region:   "eu-west-1",
+		Password: "opensesame",
+		Server: &testServer{
+			Name:   "web",
+			Ports:  []int64{80, 443},
+			Labels: map[string]string{"tier": "frontend"},
+			Weight: &weight,
+		},
+		Secrets: &testServer{Name: "vault"},
+		Extra:   map[string]interface{}{"tags": []interface{}{"a", "b"}},
+		Raw:     Dynamic(`{"replicas":3}`),
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	v := newTestVariables()
+	c := DeepCopy(v)
+	assert.Equal(t, v, c)
+	assert.True(t, Equal(v, c))
+
+	c.Server.Ports[0] = 8080
+	c.Server.Labels["tier"] = "backend"
+	*c.Server.Weight = 2
+	c.Extra.(map[string]interface{})["tags"].([]interface{})[0] = "z"
+	c.Raw[1] = ' '
+
+	assert.Equal(t, newTestVariables(), v, "modifying the copy changed the original")
+	assert.Nil(t, DeepCopy[*testServer](nil))
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(v *testVariables)
+		expected []string
+	}{
+		{
+			name:     "no changes",
+			modify:   func(v *testVariables) {},
+			expected: nil,
+		},
+		{
+			name: "scalars and nested attributes",
+			modify: func(v *testVariables) {
+				v.Region = "us-east-1"
+				v.Server.Ports[1] = 8443
+				*v.Server.Weight = 2
+			},
+			expected: []string{
+				`region: "eu-west-1" -> "us-east-1"`,
+				`server.ports[1]: 443 -> 8443`,
+				`server.weight: 1 -> 2`,
+			},
+		},
+		{
+			name: "added, removed and resized values",
+			modify: func(v *testVariables) {
+				v.Server.Labels["env"] = "prod"
+				delete(v.Server.Labels, "tier")
+				v.Server.Ports = append(v.Server.Ports, 8080)
+				v.Server.Weight = nil
+			},
+			expected: []string{
+				`server.labels["env"]: null -> "prod"`,
+				`server.labels["tier"]: "frontend" -> null`,
+				`server.ports: [80,443] -> [80,443,8080]`,
+				`server.weight: 1 -> null`,
+			},
+		},
+		{
+			name: "any values",
+			modify: func(v *testVariables) {
+				v.Extra.(map[string]interface{})["tags"] = []interface{}{"a", "c"}
+				v.Raw = Dynamic(`{"replicas":4}`)
+			},
+			expected: []string{
+				`extra["tags"][1]: "b" -> "c"`,
+				`raw: {"replicas":3} -> {"replicas":4}`,
+			},
+		},
+		{
+			name: "any values encoded differently",
+			modify: func(v *testVariables) {
+				v.Raw = Dynamic(`{ "replicas": 3 }`)
+			},
+			expected: nil,
+		},
+		{
+			name: "sensitive values are redacted",
+			modify: func(v *testVariables) {
+				v.Password = "hunter2"
+				v.Secrets.Name = "hunter2"
+			},
+			expected: []string{
+				`password: (sensitive value)`,
+				`secrets.name: (sensitive value)`,
+			},
+		},
+		{
+			name: "nil and empty lists and maps",
+			modify: func(v *testVariables) {
+				v.Server.Labels = nil
+				v.Server.Ports = []int64{}
+				v.Secrets.Ports = []int64{}
+				v.Secrets.Labels = map[string]string{}
+			},
+			expected: []string{
+				`server.labels["tier"]: "frontend" -> null`,
+				`server.ports: [80,443] -> []`,
+			},
+		},
+		{
+			name: "variables set to null",
+			modify: func(v *testVariables) {
+				v.nulls = map[string]bool{"region": true}
+			},
+			expected: []string{
+				`region: "eu-west-1" -> null`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestVariables()
+			b := DeepCopy(a)
+			tt.modify(&b)
+
+			var changes []string
+			for _, c := range Diff(a, b) {
+				changes = append(changes, c.String())
+			}
+			assert.Equal(t, tt.expected, changes)
+			assert.Equal(t, tt.expected == nil, Equal(a, b))
+		})
+	}
+}
+
+func TestDiffNeverIncludesSensitiveValues(t *testing.T) {
+	a := testOutputs{Name: json.RawMessage(`"web"`), Secret: json.RawMessage(`"opensesame"`)}
+	b := testOutputs{Name: json.RawMessage(`"api"`), Secret: json.RawMessage(`"hunter2"`)}
+
+	changes := Diff(a, b)
+	assert.Equal(t, []Change{
+		{Path: "name", Old: json.RawMessage(`"web"`), New: json.RawMessage(`"api"`)},
+		{Path: "secret", Sensitive: true},
+	}, changes)
+	assert.Equal(t, `secret: (sensitive value)`, changes[1].String())
+}
@@ -0,0 +1,91 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Dynamic holds the JSON encoding of a value of Terraform's any type, whose
+// actual type is only chosen when the module is used. The typed accessors
+// decode the value on demand.
+//
+// The empty Dynamic is omitted by omitempty, while a Dynamic holding null
+// sets the variable to null.
+type Dynamic json.RawMessage
+
+// DynamicOf returns a Dynamic holding the JSON encoding of v.
+func DynamicOf(v interface{}) (Dynamic, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Dynamic(b), nil
+}
+
+// MarshalJSON returns the JSON encoding held by d, or null when d is empty.
+func (d Dynamic) MarshalJSON() ([]byte, error) {
+	if len(d) == 0 {
+		return []byte("null"), nil
+	}
+	return d, nil
+}
+
+// UnmarshalJSON sets d to a copy of data.
+func (d *Dynamic) UnmarshalJSON(data []byte) error {
+	if d == nil {
+		return fmt.Errorf("terraform.Dynamic: UnmarshalJSON on nil pointer")
+	}
+	*d = append((*d)[0:0], data...)
+	return nil
+}
+
+// IsNull reports whether d is empty or holds null.
+func (d Dynamic) IsNull() bool {
+	return len(d) == 0 || bytes.Equal(bytes.TrimSpace(d), []byte("null"))
+}
+
+// String returns the JSON encoding held by d.
+func (d Dynamic) String() string {
+	b, _ := d.MarshalJSON()
+	return string(b)
+}
+
+// AsString decodes d as a string.
+func (d Dynamic) AsString() (string, error) {
+	return Decode[string](d)
+}
+
+// AsNumber decodes d as a number.
+func (d Dynamic) AsNumber() (float64, error) {
+	return Decode[float64](d)
+}
+
+// AsBool decodes d as a bool.
+func (d Dynamic) AsBool() (bool, error) {
+	return Decode[bool](d)
+}
+
+// AsList decodes d as a list or tuple, whose elements may be of any type.
+func (d Dynamic) AsList() ([]Dynamic, error) {
+	return Decode[[]Dynamic](d)
+}
+
+// AsMap decodes d as a map or object, whose elements may be of any type.
+func (d Dynamic) AsMap() (map[string]Dynamic, error) {
+	return Decode[map[string]Dynamic](d)
+}
+
+// Decode decodes d into a value of type T with encoding/json. Null decodes
+// to the zero value of T.
+func Decode[T any](d Dynamic) (T, error) {
+	var v T
+	if d.IsNull() {
+		return v, nil
+	}
+
+	if err := json.Unmarshal(d, &v); err != nil {
+		return v, fmt.Errorf("cannot decode %s as %T: %v", d.String(), v, err)
+	}
+	return v, nil
+}
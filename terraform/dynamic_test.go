@@ -0,0 +1,61 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamic(t *testing.T) {
+	var v struct {
+		Name   Dynamic            `json:"name,omitempty"`
+		Ports  Dynamic            `json:"ports,omitempty"`
+		Labels map[string]Dynamic `json:"labels,omitempty"`
+		Unset  Dynamic            `json:"unset,omitempty"`
+		Null   Dynamic            `json:"null,omitempty"`
+	}
+
+	err := json.Unmarshal([]byte(`{"name": "web", "ports": [80, 443], "labels": {"tier": "frontend", "replicas": 3}, "null": null}`), &v)
+	assert.NoError(t, err)
+
+	name, err := v.Name.AsString()
+	assert.NoError(t, err)
+	assert.Equal(t, "web", name)
+
+	ports, err := v.Ports.AsList()
+	assert.NoError(t, err)
+	if assert.Len(t, ports, 2) {
+		port, err := ports[1].AsNumber()
+		assert.NoError(t, err)
+		assert.Equal(t, float64(443), port)
+	}
+
+	typedPorts, err := Decode[[]int](v.Ports)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{80, 443}, typedPorts)
+
+	tier, err := v.Labels["tier"].AsString()
+	assert.NoError(t, err)
+	assert.Equal(t, "frontend", tier)
+
+	_, err = v.Labels["replicas"].AsBool()
+	assert.EqualError(t, err, "cannot decode 3 as bool: json: cannot unmarshal number into Go value of type bool")
+
+	assert.True(t, v.Unset.IsNull())
+	assert.True(t, v.Null.IsNull())
+	assert.False(t, v.Name.IsNull())
+
+	// An unset Dynamic is omitted, while null is kept
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "web", "ports": [80, 443], "labels": {"tier": "frontend", "replicas": 3}, "null": null}`, string(b))
+
+	d, err := DynamicOf(map[string]interface{}{"enabled": true})
+	assert.NoError(t, err)
+	settings, err := d.AsMap()
+	assert.NoError(t, err)
+	enabled, err := settings["enabled"].AsBool()
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+}
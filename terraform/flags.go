@@ -0,0 +1,112 @@
+package terraform
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// variableFlag is a flag.Value setting a variable.
+type variableFlag struct {
+	name     string
+	typeExpr string
+	p        interface{}
+}
+
+// VariableFlag returns a flag.Value that sets the variable name, of the type
+// given by typeExpr, in the field p points to. Values are parsed as by
+// Terraform's -var option: values of primitive types and of the any type are
+// taken literally, while others are HCL expressions such as ["a", "b"].
+func VariableFlag(name, typeExpr string, p interface{}) flag.Value {
+	return &variableFlag{name: name, typeExpr: typeExpr, p: p}
+}
+
+func (f *variableFlag) String() string {
+	if f == nil || f.p == nil {
+		return ""
+	}
+
+	v := reflect.ValueOf(f.p).Elem()
+	if v.IsZero() {
+		return ""
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func (f *variableFlag) Set(s string) error {
+	val, err := envValue("-"+f.name, s, f.typeExpr)
+	if err != nil {
+		return err
+	}
+	b, err := convertVariable(f.name, val, f.typeExpr)
+	if err != nil {
+		return err
+	}
+
+	// Decoding into a map or slice that already holds the default would
+	// merge the two
+	v := reflect.ValueOf(f.p).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return json.Unmarshal(b, f.p)
+}
+
+// IsBoolFlag lets bool variables be set to true by the flag alone.
+func (f *variableFlag) IsBoolFlag() bool {
+	return f.typeExpr == "bool"
+}
+
+// sensitiveFlag hides the value of a flag in usage messages.
+type sensitiveFlag struct {
+	flag.Value
+}
+
+// SensitiveFlag returns a flag.Value that sets the same value as v, but
+// doesn't show it as the default in usage messages.
+func SensitiveFlag(v flag.Value) flag.Value {
+	return &sensitiveFlag{v}
+}
+
+func (f *sensitiveFlag) String() string {
+	return ""
+}
+
+func (f *sensitiveFlag) IsBoolFlag() bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// RequireFlags returns an error naming the flags among names that weren't
+// set on the command line parsed by fs.
+func RequireFlags(fs *flag.FlagSet, names ...string) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var missing []string
+	for _, name := range names {
+		if !set[name] {
+			missing = append(missing, "-"+name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+	if len(missing) == 1 {
+		return fmt.Errorf("missing required flag %s", missing[0])
+	}
+	return fmt.Errorf("missing required flags %s", strings.Join(missing, ", "))
+}
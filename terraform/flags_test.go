@@ -0,0 +1,84 @@
+package terraform
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariableFlag(t *testing.T) {
+	enabled := true
+	v := struct {
+		Region   string
+		Port     int64
+		Enabled  *bool
+		Tags     map[string]string
+		Server   *testServer
+		Extra    interface{}
+		Password string
+	}{
+		Region:   "eu-west-1",
+		Enabled:  &enabled,
+		Tags:     map[string]string{"env": "dev"},
+		Password: "opensesame",
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(VariableFlag("region", "string", &v.Region), "region", "")
+	fs.Var(VariableFlag("port", "number", &v.Port), "port", "")
+	fs.Var(VariableFlag("enabled", "bool", &v.Enabled), "enabled", "")
+	fs.Var(VariableFlag("tags", "map(string)", &v.Tags), "tags", "")
+	fs.Var(VariableFlag("server", "object({name = string, ports = list(number), weight = optional(number, 1)})", &v.Server), "server", "")
+	fs.Var(VariableFlag("extra", "any", &v.Extra), "extra", "")
+	fs.Var(SensitiveFlag(VariableFlag("password", "string", &v.Password)), "password", "")
+
+	assert.Equal(t, "eu-west-1", fs.Lookup("region").DefValue)
+	assert.Equal(t, "true", fs.Lookup("enabled").DefValue)
+	assert.Equal(t, `{"env":"dev"}`, fs.Lookup("tags").DefValue)
+	assert.Equal(t, "", fs.Lookup("password").DefValue)
+
+	err := fs.Parse([]string{
+		"-region", "us-east-1",
+		"-port=8080",
+		"-enabled=false",
+		"-tags", `{team = "platform"}`,
+		"-server", `{name = "web", ports = [80, "443"]}`,
+		"-extra", `["a"]`,
+		"-password", "hunter2",
+	})
+	assert.NoError(t, err)
+
+	weight := int64(1)
+	assert.Equal(t, "us-east-1", v.Region)
+	assert.Equal(t, int64(8080), v.Port)
+	assert.False(t, *v.Enabled)
+	assert.Equal(t, map[string]string{"team": "platform"}, v.Tags)
+	assert.Equal(t, &testServer{Name: "web", Ports: []int64{80, 443}, Weight: &weight}, v.Server)
+	assert.Equal(t, `["a"]`, v.Extra)
+	assert.Equal(t, "hunter2", v.Password)
+
+	err = fs.Parse([]string{"-enabled"})
+	assert.NoError(t, err)
+	assert.True(t, *v.Enabled)
+
+	var out bytes.Buffer
+	fs.SetOutput(&out)
+	err = fs.Parse([]string{"-server", `{name = "web"}`})
+	assert.EqualError(t, err, `invalid value "{name = \"web\"}" for flag -server: invalid value for variable "server": attribute "ports" is required`)
+}
+
+func TestRequireFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("a", "", "")
+	fs.String("b", "", "")
+	fs.String("c", "", "")
+
+	err := fs.Parse([]string{"-b", "x"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, RequireFlags(fs, "b"))
+	assert.EqualError(t, RequireFlags(fs, "a", "b"), "missing required flag -a")
+	assert.EqualError(t, RequireFlags(fs, "c", "b", "a"), "missing required flags -a, -c")
+}
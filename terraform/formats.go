@@ -0,0 +1,153 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// The YAML and TOML helpers convert values through their JSON encoding, so
+// that files use the Terraform names of variables and outputs, and values
+// set to null, outputs and values of the any type are handled the same way
+// as in JSON. Files are decoded with DecodeStrict.
+
+// LoadYAML reads the YAML file at path into v, which must be a pointer to a
+// generated type such as Variables or Outputs.
+func LoadYAML(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return decodeDocument(path, doc, v)
+}
+
+// SaveYAML writes v, a generated type such as Variables or Outputs, to the
+// YAML file at path.
+func SaveYAML(path string, v interface{}) error {
+	doc, err := encodeDocument(v)
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// LoadTOML reads the TOML file at path into v, which must be a pointer to a
+// generated type such as Variables or Outputs.
+func LoadTOML(path string, v interface{}) error {
+	var doc map[string]interface{}
+	if _, err := toml.DecodeFile(path, &doc); err != nil {
+		return err
+	}
+	return decodeDocument(path, doc, v)
+}
+
+// SaveTOML writes v, a generated type such as Variables or Outputs, to the
+// TOML file at path. TOML has no null, so values set to null can't be
+// written.
+func SaveTOML(path string, v interface{}) error {
+	doc, err := encodeDocument(v)
+	if err != nil {
+		return err
+	}
+
+	if path, ok := findNull(doc, ""); ok {
+		return fmt.Errorf("cannot write %s to TOML, which has no null", path)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(doc); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// decodeDocument decodes a document decoded from YAML or TOML into v through
+// its JSON encoding.
+func decodeDocument(path string, doc, v interface{}) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if err := DecodeStrict(bytes.NewReader(b), v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// encodeDocument returns the JSON encoding of v decoded into plain Go values,
+// with whole numbers as int64 and other numbers as float64.
+func encodeDocument(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return plainNumbers(doc), nil
+}
+
+// findNull returns the attribute path of the first null within v.
+func findNull(v interface{}, path string) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return path, true
+	case []interface{}:
+		for i, el := range v {
+			if p, ok := findNull(el, fmt.Sprintf("%s[%d]", path, i)); ok {
+				return p, true
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if p, ok := findNull(v[k], joinPath(path, k)); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+func plainNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i, el := range v {
+			v[i] = plainNumbers(el)
+		}
+	case map[string]interface{}:
+		for k, el := range v {
+			v[k] = plainNumbers(el)
+		}
+	}
+	return v
+}
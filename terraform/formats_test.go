@@ -0,0 +1,81 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "variables.yaml")
+
+	weight := int64(2)
+	v := mergeVariables{
+		Region: "eu-west-1",
+		Server: &testServer{Name: "web", Ports: []int64{80, 443}, Weight: &weight},
+		Tags:   map[string]string{"app.kubernetes.io/name": "web"},
+		Extra:  map[string]interface{}{"ratio": 0.5, "tags": []interface{}{"a", nil}},
+	}
+	assert.NoError(t, v.SetNull("ports"))
+
+	err := SaveYAML(path, v)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `extra:
+    ratio: 0.5
+    tags:
+        - a
+        - null
+ports: null
+region: eu-west-1
+server:
+    name: web
+    ports:
+        - 80
+        - 443
+    weight: 2
+tags:
+    app.kubernetes.io/name: web
+`, string(b))
+
+	var loaded mergeVariables
+	err = LoadYAML(path, &loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, v, loaded)
+
+	err = os.WriteFile(path, []byte("region: 1\nzone: a\n"), 0o644)
+	assert.NoError(t, err)
+	err = LoadYAML(path, &loaded)
+	assert.EqualError(t, err, path+`: region: expected a string, got a number
+zone: unknown variable`)
+}
+
+func TestTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outputs.toml")
+
+	o := testOutputs{Name: json.RawMessage(`"web"`), Secret: json.RawMessage(`{"token":"opensesame","ttl":60}`)}
+	err := SaveTOML(path, o)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `name = "web"
+
+[secret]
+  token = "opensesame"
+  ttl = 60
+`, string(b))
+
+	var loaded testOutputs
+	err = LoadTOML(path, &loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, o, loaded)
+
+	err = SaveTOML(path, mergeVariables{Extra: []interface{}{1, nil}})
+	assert.EqualError(t, err, "cannot write extra[1] to TOML, which has no null")
+}
@@ -1,6 +1,9 @@
 package lexer
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/lolabyte/tf2go/terraform/token"
 )
 
@@ -9,15 +12,20 @@ type Lexer struct {
 	currPosition int  // current position in the input (current char)
 	readPosition int  // current reading position in the input (after current char)
 	ch           byte // current char
+	line         int  // line of the current char
+	column       int  // column of the current char
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
 func (l *Lexer) peek() byte {
+	if l.readPosition >= len(l.input) {
+		return 0
+	}
 	return l.input[l.readPosition]
 }
 
@@ -26,6 +34,12 @@ func (l *Lexer) isAtEnd() bool {
 }
 
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -43,13 +57,25 @@ func (l *Lexer) readChars(n int) {
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
-
 	l.skipWhitespace()
 
+	line, column := l.line, l.column
+	tok := l.readToken()
+	tok.Line, tok.Column = line, column
+
+	return tok
+}
+
+func (l *Lexer) readToken() token.Token {
+	var tok token.Token
+
 	switch l.ch {
 	case '=':
 		tok = newToken(token.ASSIGN, l.ch)
+	case ':':
+		tok = newToken(token.COLON, l.ch)
+	case '-':
+		tok = newToken(token.MINUS, l.ch)
 	case '(':
 		tok = newToken(token.LEFT_PAREN, l.ch)
 	case ')':
@@ -65,13 +91,25 @@ func (l *Lexer) NextToken() token.Token {
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
 	case '"', '\'':
+		value, ok := l.readString(l.ch)
+		if !ok {
+			return token.Token{Type: token.ERROR, Literal: "unterminated string"}
+		}
 		tok.Type = token.STRING
-		tok.Literal = l.readString(l.ch)
+		tok.Literal = value
+		return tok
+	case '<':
+		if l.peek() != '<' {
+			tok = newToken(token.ILLEGAL, l.ch)
+			break
+		}
+		value, ok := l.readHeredoc()
+		if !ok {
+			return token.Token{Type: token.ERROR, Literal: "unterminated heredoc"}
+		}
+		tok.Type = token.HEREDOC
+		tok.Literal = value
 		return tok
-	// TODO: Add support for comments
-	//case '/', '#':
-	//	tok.Type = token.COMMENT
-	//	tok.Literal = l.readComment()
 	case 0:
 		tok.Type = token.EOF
 		tok.Literal = ""
@@ -94,35 +132,218 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
+// skipWhitespace skips whitespace and comments, which may appear anywhere
+// between tokens.
 func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
-		l.readChar()
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r':
+			l.readChar()
+		case l.ch == '#' || l.ch == '/' && l.peek() == '/':
+			for l.ch != '\n' && l.ch != 0 {
+				l.readChar()
+			}
+		case l.ch == '/' && l.peek() == '*':
+			l.readChars(2)
+			for !(l.ch == '*' && l.peek() == '/') && l.ch != 0 {
+				l.readChar()
+			}
+			l.readChars(2)
+		default:
+			return
+		}
 	}
 }
 
+// readNumber reads a number with an optional fraction and exponent, such as
+// 42, 3.14 or 1e-3. A sign is lexed separately as token.MINUS.
 func (l *Lexer) readNumber() string {
 	start := l.currPosition
-	// TODO: add support for fractional numbers (e.g 3.14)
-	for isDigit(l.ch) {
+	l.readDigits()
+
+	if l.ch == '.' && isDigit(l.peek()) {
 		l.readChar()
+		l.readDigits()
 	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		exponent := l.readPosition
+		if exponent < len(l.input) && (l.input[exponent] == '+' || l.input[exponent] == '-') {
+			exponent++
+		}
+		if exponent < len(l.input) && isDigit(l.input[exponent]) {
+			l.readChars(exponent - l.currPosition)
+			l.readDigits()
+		}
+	}
+
 	return l.input[start:l.currPosition]
 }
 
-func (l *Lexer) readString(quoteCh byte) string {
-	start := l.currPosition
-	for l.peek() != quoteCh && !l.isAtEnd() {
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) {
 		l.readChar()
 	}
-	s := l.input[start+1 : l.currPosition-1]
+}
+
+// readString reads a string up to its closing quote, reporting whether there
+// is one before the end of the input.
+func (l *Lexer) readString(quoteCh byte) (string, bool) {
+	var out strings.Builder
+
+	for {
+		l.readChar()
+		if l.ch == 0 {
+			return "", false
+		}
+		if l.ch == quoteCh {
+			break
+		}
+
+		switch {
+		case l.ch == '\\':
+			l.readEscape(&out)
+		case strings.HasPrefix(l.input[l.currPosition:], "$${"), strings.HasPrefix(l.input[l.currPosition:], "%%{"):
+			// An escaped template sequence, which is kept as a literal "${" or "%{"
+			out.WriteByte(l.ch)
+			l.readChar()
+		default:
+			out.WriteByte(l.ch)
+		}
+	}
+
 	// advance to consume the terminating quote
+	l.readChar()
+	return out.String(), true
+}
+
+// readEscape decodes the escape sequence following a backslash in a string.
+func (l *Lexer) readEscape(out *strings.Builder) {
+	l.readChar()
+
+	switch l.ch {
+	case 'n':
+		out.WriteByte('\n')
+	case 'r':
+		out.WriteByte('\r')
+	case 't':
+		out.WriteByte('\t')
+	case 'u', 'U':
+		digits := 4
+		if l.ch == 'U' {
+			digits = 8
+		}
+
+		end := l.readPosition + digits
+		if end > len(l.input) {
+			end = len(l.input)
+		}
+
+		r, err := strconv.ParseUint(l.input[l.readPosition:end], 16, 32)
+		if err != nil {
+			// Not a valid unicode escape, keep it as written
+			out.WriteByte('\\')
+			out.WriteByte(l.ch)
+			return
+		}
+
+		out.WriteRune(rune(r))
+		l.readChars(digits)
+	case 0:
+		// Unterminated string, readString stops at the EOF
+	default:
+		out.WriteByte(l.ch)
+	}
+}
+
+// readHeredoc reads a heredoc string such as
+//
+//	<<EOT
+//	hello
+//	EOT
+//
+// returning its lines up to the closing marker, each ending in a newline, and
+// whether there is a closing marker before the end of the input. An indented
+// heredoc (<<-EOT) has the smallest indentation of its lines removed, as in
+// Terraform.
+func (l *Lexer) readHeredoc() (string, bool) {
 	l.readChars(2)
-	return s
+
+	indented := l.ch == '-'
+	if indented {
+		l.readChar()
+	}
+
+	marker := l.readIdentifier()
+
+	// The rest of the opening line must be empty
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+
+	var lines []string
+	closed := false
+	for l.ch != 0 {
+		l.readChar()
+
+		start := l.currPosition
+		for l.ch != '\n' && l.ch != 0 {
+			l.readChar()
+		}
+		line := strings.TrimSuffix(l.input[start:l.currPosition], "\r")
+
+		if strings.TrimSpace(line) == marker {
+			l.readChar()
+			closed = true
+			break
+		}
+		lines = append(lines, line)
+	}
+	if !closed {
+		return "", false
+	}
+
+	if indented {
+		lines = unindent(lines)
+	}
+
+	var out strings.Builder
+	for _, line := range lines {
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+
+	return out.String(), true
+}
+
+// unindent removes the smallest indentation shared by all non-blank lines.
+func unindent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			out[i] = ""
+		} else {
+			out[i] = line[indent:]
+		}
+	}
+
+	return out
 }
 
 func (l *Lexer) readIdentifier() string {
 	start := l.currPosition
-	for isLetter(l.ch) {
+	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '-' {
 		l.readChar()
 	}
 	return l.input[start:l.currPosition]
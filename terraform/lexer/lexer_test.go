@@ -59,6 +59,70 @@ func TestNextToken(t *testing.T) {
 				{token.NUMBER, "99"},
 			},
 		},
+		{
+			title: "Fractional and negative number literals",
+			input: "3.14 -1 1e3 2.5E-2 1.",
+			tokens: []tok{
+				{token.NUMBER, "3.14"},
+				{token.MINUS, "-"},
+				{token.NUMBER, "1"},
+				{token.NUMBER, "1e3"},
+				{token.NUMBER, "2.5E-2"},
+				{token.NUMBER, "1"},
+				{token.ILLEGAL, "."},
+			},
+		},
+		{
+			title: "Heredocs",
+			input: "[<<EOT\nhello\n  world\nEOT\n, <<-EOT\n    indented\n      more\n    EOT\n]",
+			tokens: []tok{
+				{token.LEFT_SQUARE_BRACE, "["},
+				{token.HEREDOC, "hello\n  world\n"},
+				{token.COMMA, ","},
+				{token.HEREDOC, "indented\n  more\n"},
+				{token.RIGHT_SQUARE_BRACE, "]"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			title: "String without its closing quote",
+			input: "[\"abc",
+			tokens: []tok{
+				{token.LEFT_SQUARE_BRACE, "["},
+				{token.ERROR, "unterminated string"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			title: "Heredoc without its closing marker",
+			input: "<<EOT\nhello\n",
+			tokens: []tok{
+				{token.ERROR, "unterminated heredoc"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			title: "Comments and colons",
+			input: "{ # comment\n\"a\": 1 // comment\n/* multi\nline */ b = 2 }",
+			tokens: []tok{
+				{token.LEFT_CURLY_BRACE, "{"},
+				{token.STRING, "a"},
+				{token.COLON, ":"},
+				{token.NUMBER, "1"},
+				{token.IDENT, "b"},
+				{token.ASSIGN, "="},
+				{token.NUMBER, "2"},
+				{token.RIGHT_CURLY_BRACE, "}"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			title: "String literal with escapes",
+			input: `"say \"hi\"\n\\ $${var} %%{if} \u00e9"`,
+			tokens: []tok{
+				{token.STRING, "say \"hi\"\n\\ ${var} %{if} \u00e9"},
+			},
+		},
 		{
 			input: `list(object({
 				name    = string
@@ -117,6 +181,9 @@ func TestNextToken(t *testing.T) {
 				if tkn.Type != expected.expectedType {
 					t.Fatalf("token #%d has wrong token.Type, expected=%q, got=%q", i, expected.expectedType, tkn.Type)
 				}
+				if tkn.Literal != expected.expectedLiteral {
+					t.Fatalf("token #%d has wrong token.Literal, expected=%q, got=%q", i, expected.expectedLiteral, tkn.Literal)
+				}
 			}
 		})
 	}
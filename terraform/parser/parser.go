@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/lolabyte/tf2go/terraform/ast"
@@ -21,13 +22,32 @@ type TypeParser struct {
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 
-	errors []string
+	errors   []string
+	warnings []string
+
+	// valueMode is set while parsing a literal value, which may not contain
+	// type keywords or identifiers other than object keys
+	valueMode bool
+}
+
+// valueTokens are the tokens a literal value may start with.
+var valueTokens = map[token.TokenType]bool{
+	token.TRUE:              true,
+	token.FALSE:             true,
+	token.NULL:              true,
+	token.NUMBER:            true,
+	token.MINUS:             true,
+	token.STRING:            true,
+	token.HEREDOC:           true,
+	token.LEFT_SQUARE_BRACE: true,
+	token.LEFT_CURLY_BRACE:  true,
 }
 
 func New(l *lexer.Lexer) *TypeParser {
 	p := &TypeParser{
 		lex:            l,
 		errors:         []string{},
+		warnings:       []string{},
 		prefixParseFns: make(map[token.TokenType]prefixParseFn),
 	}
 
@@ -35,7 +55,10 @@ func New(l *lexer.Lexer) *TypeParser {
 	p.registerPrefix(token.TRUE, p.parseBool)
 	p.registerPrefix(token.FALSE, p.parseBool)
 	p.registerPrefix(token.NUMBER, p.parseNumberLiteral)
+	p.registerPrefix(token.MINUS, p.parseNegativeNumber)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.HEREDOC, p.parseStringLiteral)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
 	p.registerPrefix(token.LEFT_SQUARE_BRACE, p.parseListLiteral)
 	p.registerPrefix(token.LEFT_CURLY_BRACE, p.parseObjectLiteral)
 
@@ -60,6 +83,10 @@ func (p *TypeParser) ParseType() *ast.Type {
 		Statements: []ast.Statement{},
 	}
 
+	if p.currTokenIs(token.STRING) && p.peekTokenIs(token.EOF) {
+		return p.parseLegacyType()
+	}
+
 	for !p.currTokenIs(token.EOF) {
 		s := p.parseStatement()
 		if s != nil {
@@ -71,10 +98,32 @@ func (p *TypeParser) ParseType() *ast.Type {
 	return t
 }
 
+// ParseValue parses a literal value, such as the default value of a variable
+// or of an optional attribute: a string or heredoc, a number, a bool, null, or
+// a list or object of values. Object keys may be identifiers or quoted
+// strings, separated from their values by "=" or ":", so JSON is accepted as
+// well.
+func (p *TypeParser) ParseValue() ast.Expression {
+	p.valueMode = true
+
+	exp := p.parseExpression()
+	if !p.peekTokenIs(token.EOF) {
+		p.errorf(p.peekToken, "unexpected %q after value", p.peekToken.Literal)
+	}
+
+	return exp
+}
+
 func (p *TypeParser) Errors() []string {
 	return p.errors
 }
 
+// Warnings returns the non-fatal problems found while parsing, such as the
+// use of deprecated Terraform 0.11 type syntax.
+func (p *TypeParser) Warnings() []string {
+	return p.warnings
+}
+
 func (p *TypeParser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
@@ -92,6 +141,14 @@ func (p *TypeParser) peekTokenIs(t token.TokenType) bool {
 	return p.peekToken.Type == t
 }
 
+func (p *TypeParser) peekTokenIsDelimiter() bool {
+	switch p.peekToken.Type {
+	case token.COMMA, token.RIGHT_PAREN, token.RIGHT_CURLY_BRACE, token.RIGHT_SQUARE_BRACE, token.EOF:
+		return true
+	}
+	return false
+}
+
 func (p *TypeParser) expectPeek(t token.TokenType) bool {
 	if p.peekTokenIs(t) {
 		p.nextToken()
@@ -102,6 +159,16 @@ func (p *TypeParser) expectPeek(t token.TokenType) bool {
 	}
 }
 
+// expectAssign is like expectPeek(token.ASSIGN), but also accepts the colon
+// that may separate the keys and values of an object literal.
+func (p *TypeParser) expectAssign() bool {
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		return true
+	}
+	return p.expectPeek(token.ASSIGN)
+}
+
 func (p *TypeParser) parseStatement() ast.Statement {
 	return p.parseExpressionStatement()
 }
@@ -116,8 +183,17 @@ func (p *TypeParser) parseExpressionStatement() ast.Statement {
 func (p *TypeParser) parseExpression() ast.Expression {
 	prefix := p.prefixParseFns[p.currToken.Type]
 	if prefix == nil {
-		// TODO: error
-		return nil
+		p.noPrefixParseFnError(p.currToken)
+		return p.parseBadExpression()
+	}
+
+	if p.valueMode && !valueTokens[p.currToken.Type] {
+		p.errorf(p.currToken, "expected a literal value, got %q", p.currToken.Literal)
+		if p.peekTokenIs(token.LEFT_PAREN) {
+			// Skip the arguments of a type constructor
+			p.nextToken()
+		}
+		return p.parseBadExpression()
 	}
 
 	leftExp := prefix()
@@ -126,6 +202,67 @@ func (p *TypeParser) parseExpression() ast.Expression {
 
 }
 
+// parseNextExpression advances to the next token and parses the expression
+// starting there. If the next token is a comma or closing delimiter, as in
+// "{ a = }", the expression is missing and a BadExpression is returned
+// without advancing, so that the caller can carry on from the delimiter.
+func (p *TypeParser) parseNextExpression() ast.Expression {
+	if p.peekTokenIsDelimiter() {
+		p.noPrefixParseFnError(p.peekToken)
+		return &ast.BadExpression{Token: p.peekToken}
+	}
+
+	p.nextToken()
+	return p.parseExpression()
+}
+
+// parseBadExpression returns a BadExpression in place of an expression that
+// can't be parsed, starting at the current token. If that token opens a
+// group, the whole group is skipped.
+func (p *TypeParser) parseBadExpression() ast.Expression {
+	bad := &ast.BadExpression{Token: p.currToken}
+
+	depth := 0
+	switch p.currToken.Type {
+	case token.LEFT_PAREN, token.LEFT_CURLY_BRACE, token.LEFT_SQUARE_BRACE:
+		depth = 1
+	}
+
+	for depth > 0 && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+
+		switch p.currToken.Type {
+		case token.LEFT_PAREN, token.LEFT_CURLY_BRACE, token.LEFT_SQUARE_BRACE:
+			depth++
+		case token.RIGHT_PAREN, token.RIGHT_CURLY_BRACE, token.RIGHT_SQUARE_BRACE:
+			depth--
+		}
+	}
+
+	return bad
+}
+
+// synchronize skips tokens until the next token is a comma or closing
+// delimiter at the given nesting depth relative to the current token, or the
+// end of the input. This lets the parser recover from an error and carry on
+// with the next element of the enclosing list, object or type constructor.
+func (p *TypeParser) synchronize(depth int) {
+	for !p.peekTokenIs(token.EOF) {
+		if depth <= 0 && p.peekTokenIsDelimiter() {
+			return
+		}
+
+		p.nextToken()
+
+		switch p.currToken.Type {
+		case token.LEFT_PAREN, token.LEFT_CURLY_BRACE, token.LEFT_SQUARE_BRACE:
+			depth++
+		case token.RIGHT_PAREN, token.RIGHT_CURLY_BRACE, token.RIGHT_SQUARE_BRACE:
+			depth--
+		}
+	}
+}
+
 func (p *TypeParser) parseExpressionList(end token.TokenType) []ast.Expression {
 	list := []ast.Expression{}
 
@@ -134,17 +271,27 @@ func (p *TypeParser) parseExpressionList(end token.TokenType) []ast.Expression {
 		return list
 	}
 
-	p.nextToken()
-	list = append(list, p.parseExpression())
+	list = append(list, p.parseNextExpression())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
-		p.nextToken()
-		list = append(list, p.parseExpression())
+		if p.peekTokenIs(end) {
+			// Allow a trailing comma
+			break
+		}
+		list = append(list, p.parseNextExpression())
 	}
 
 	if !p.expectPeek(end) {
-		return nil
+		// Skip anything else up to the end of the list
+		p.synchronize(0)
+		for p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+			p.synchronize(0)
+		}
+		if p.peekTokenIs(end) {
+			p.nextToken()
+		}
 	}
 
 	return list
@@ -159,9 +306,8 @@ func (p *TypeParser) parseBool() ast.Expression {
 
 	b, err := strconv.ParseBool(p.currToken.Literal)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as bool", p.currToken.Literal)
-		p.errors = append(p.errors, msg)
-		return nil
+		p.errorf(p.currToken, "could not parse %q as bool", p.currToken.Literal)
+		return &ast.BadExpression{Token: p.currToken}
 	}
 
 	boo.Value = b
@@ -172,11 +318,11 @@ func (p *TypeParser) parseBool() ast.Expression {
 func (p *TypeParser) parseNumberLiteral() ast.Expression {
 	lit := &ast.NumberLiteral{Token: p.currToken}
 
-	value, err := strconv.ParseInt(p.currToken.Literal, 0, 64)
+	// Parsed with the same precision as cty numbers
+	value, _, err := big.ParseFloat(p.currToken.Literal, 10, 512, big.ToNearestEven)
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.currToken.Literal)
-		p.errors = append(p.errors, msg)
-		return nil
+		p.errorf(p.currToken, "could not parse %q as number", p.currToken.Literal)
+		return &ast.BadExpression{Token: p.currToken}
 	}
 
 	lit.Value = value
@@ -184,10 +330,33 @@ func (p *TypeParser) parseNumberLiteral() ast.Expression {
 	return lit
 }
 
+// parseNegativeNumber parses a minus sign followed by a number literal into a
+// single negative NumberLiteral.
+func (p *TypeParser) parseNegativeNumber() ast.Expression {
+	minus := p.currToken
+
+	if !p.expectPeek(token.NUMBER) {
+		return &ast.BadExpression{Token: minus}
+	}
+
+	exp := p.parseNumberLiteral()
+	if lit, ok := exp.(*ast.NumberLiteral); ok {
+		lit.Token.Literal = "-" + lit.Token.Literal
+		lit.Token.Line, lit.Token.Column = minus.Line, minus.Column
+		lit.Value.Neg(lit.Value)
+	}
+
+	return exp
+}
+
 func (p *TypeParser) parseStringLiteral() ast.Expression {
 	return &ast.StringLiteral{Token: p.currToken, Value: p.currToken.Literal}
 }
 
+func (p *TypeParser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.currToken}
+}
+
 func (p *TypeParser) parseListLiteral() ast.Expression {
 	list := &ast.ListLiteral{
 		Token:    p.currToken,
@@ -204,15 +373,25 @@ func (p *TypeParser) parseObjectLiteral() ast.Expression {
 	obj.KVPairs = make(map[ast.Expression]ast.Expression)
 
 	for !p.peekTokenIs(token.RIGHT_CURLY_BRACE) {
-		p.nextToken()
-		key := p.parseExpression()
-
-		if !p.expectPeek(token.ASSIGN) {
-			return nil
+		if p.peekTokenIs(token.EOF) || p.peekTokenIs(token.RIGHT_PAREN) || p.peekTokenIs(token.RIGHT_SQUARE_BRACE) {
+			// Unterminated object, leave the delimiter to the enclosing expression
+			p.peekError(token.RIGHT_CURLY_BRACE)
+			return obj
 		}
 
-		p.nextToken()
-		value := p.parseExpression()
+		key := p.parseObjectKey()
+
+		var value ast.Expression
+		if _, ok := key.(*ast.BadExpression); ok {
+			// The key has been reported and skipped along with the rest of
+			// the attribute
+			value = key
+		} else if p.expectAssign() {
+			value = p.parseNextExpression()
+		} else {
+			value = &ast.BadExpression{Token: p.peekToken}
+			p.synchronize(0)
+		}
 
 		if p.peekTokenIs(token.COMMA) {
 			// Skip optional comma
@@ -226,6 +405,22 @@ func (p *TypeParser) parseObjectLiteral() ast.Expression {
 	return obj
 }
 
+// parseObjectKey parses the key of an object literal. Keywords such as
+// "string" or "list" are valid attribute names, so they are treated as plain
+// identifiers when used as a key.
+func (p *TypeParser) parseObjectKey() ast.Expression {
+	if p.peekTokenIsDelimiter() {
+		return p.parseNextExpression()
+	}
+
+	p.nextToken()
+	if (p.peekTokenIs(token.ASSIGN) || p.peekTokenIs(token.COLON)) && (p.currTokenIs(token.IDENT) || token.IsKeyword(p.currToken.Literal)) {
+		return p.parseIdentifier()
+	}
+
+	return p.parseExpression()
+}
+
 func (p *TypeParser) parseAnyTypeLiteral() ast.Expression {
 	return &ast.AnyTypeLiteral{Token: p.currToken}
 }
@@ -245,13 +440,13 @@ func (p *TypeParser) parseStringTypeLiteral() ast.Expression {
 func (p *TypeParser) parseListTypeLiteral() ast.Expression {
 	list := &ast.ListTypeLiteral{Token: p.currToken}
 
-	if !p.expectPeek(token.LEFT_PAREN) {
-		return nil
+	if !p.peekTokenIs(token.LEFT_PAREN) {
+		list.TypeExpression = p.parseLegacyElementType()
+		return list
 	}
 
 	p.nextToken()
-	list.TypeExpression = p.parseExpression()
-	p.nextToken()
+	list.TypeExpression, list.ExtraArguments = p.parseTypeArguments()
 
 	return list
 }
@@ -259,13 +454,13 @@ func (p *TypeParser) parseListTypeLiteral() ast.Expression {
 func (p *TypeParser) parseMapTypeLiteral() ast.Expression {
 	m := &ast.MapTypeLiteral{Token: p.currToken}
 
-	if !p.expectPeek(token.LEFT_PAREN) {
-		return nil
+	if !p.peekTokenIs(token.LEFT_PAREN) {
+		m.TypeExpression = p.parseLegacyElementType()
+		return m
 	}
 
 	p.nextToken()
-	m.TypeExpression = p.parseExpression()
-	p.nextToken()
+	m.TypeExpression, m.ExtraArguments = p.parseTypeArguments()
 
 	return m
 }
@@ -274,12 +469,10 @@ func (p *TypeParser) parseObjectTypeLiteral() ast.Expression {
 	obj := &ast.ObjectTypeLiteral{Token: p.currToken}
 
 	if !p.expectPeek(token.LEFT_PAREN) {
-		return nil
+		return obj
 	}
 
-	p.nextToken()
-	obj.ObjectSpec = p.parseObjectLiteral()
-	p.nextToken()
+	obj.ObjectSpec, obj.ExtraArguments = p.parseTypeArguments()
 
 	return obj
 }
@@ -288,25 +481,111 @@ func (p *TypeParser) parseOptionalTypeLiteral() ast.Expression {
 	opt := &ast.OptionalTypeLiteral{Token: p.currToken}
 
 	if !p.expectPeek(token.LEFT_PAREN) {
-		return nil
+		return opt
 	}
 
-	p.nextToken()
-	opt.TypeExpression = p.parseExpression()
+	var args []ast.Expression
+	opt.TypeExpression, args = p.parseTypeArguments()
+	if len(args) > 0 {
+		opt.DefaultValue = args[0]
+		opt.ExtraArguments = args[1:]
+	}
 
-	if p.expectPeek(token.COMMA) {
-		p.nextToken()
-		opt.DefaultValue = p.parseExpression()
+	return opt
+}
+
+// parseTypeArguments parses the parenthesized arguments of a type
+// constructor, starting at the opening parenthesis and ending on the closing
+// one. The first argument is returned separately from any others so that
+// constructors taking a single argument can keep the rest for the checker to
+// report.
+func (p *TypeParser) parseTypeArguments() (ast.Expression, []ast.Expression) {
+	args := p.parseExpressionList(token.RIGHT_PAREN)
+	if len(args) == 0 {
+		return nil, nil
 	}
 
-	p.nextToken()
+	return args[0], args[1:]
+}
 
-	return opt
+// parseLegacyType handles the Terraform 0.11 quoted type syntax (e.g.
+// type = "string") by parsing the quoted contents as a type expression.
+func (p *TypeParser) parseLegacyType() *ast.Type {
+	quoted := p.currToken.Literal
+
+	inner := New(lexer.New(quoted))
+	t := inner.ParseType()
+	p.errors = append(p.errors, inner.Errors()...)
+
+	// A quoted type that doesn't parse, or is missing a type argument the
+	// checker reports, has no replacement to suggest
+	if len(inner.Errors()) > 0 || !isCompleteType(t) {
+		return t
+	}
+
+	p.warnings = append(
+		p.warnings,
+		fmt.Sprintf("quoted type constraints are deprecated, use %s instead of %q", t.String(), quoted),
+	)
+
+	return t
 }
 
-func (p *TypeParser) peekError(t token.TokenType) {
-	p.errors = append(
-		p.errors,
-		fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type),
+// isCompleteType reports whether every type constructor of t has its type
+// argument, so that t can be printed as the replacement of a quoted type.
+func isCompleteType(t *ast.Type) bool {
+	complete := true
+	ast.Inspect(t, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BadExpression:
+			complete = false
+		case *ast.ListTypeLiteral:
+			complete = complete && n.TypeExpression != nil
+		case *ast.MapTypeLiteral:
+			complete = complete && n.TypeExpression != nil
+		case *ast.OptionalTypeLiteral:
+			complete = complete && n.TypeExpression != nil
+		case *ast.ObjectTypeLiteral:
+			_, ok := n.ObjectSpec.(*ast.ObjectLiteral)
+			complete = complete && ok
+		}
+		return complete
+	})
+	return complete
+}
+
+// parseLegacyElementType handles the Terraform 0.11 bare "list" and "map"
+// keywords, which are equivalent to list(any) and map(any).
+func (p *TypeParser) parseLegacyElementType() ast.Expression {
+	p.warnings = append(
+		p.warnings,
+		fmt.Sprintf("bare %s type is deprecated, use %s(any) instead", p.currToken.Literal, p.currToken.Literal),
 	)
+
+	return &ast.AnyTypeLiteral{Token: token.Token{Type: token.ANY_TYPE, Literal: "any"}}
+}
+
+func (p *TypeParser) errorf(tok token.Token, format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	p.errors = append(p.errors, fmt.Sprintf("%d:%d: %s", tok.Line, tok.Column, msg))
+}
+
+func (p *TypeParser) peekError(t token.TokenType) {
+	if p.peekTokenIs(token.ERROR) {
+		p.errorf(p.peekToken, "%s", p.peekToken.Literal)
+		return
+	}
+	p.errorf(p.peekToken, "expected next token to be %s, got %s instead", t, p.peekToken.Type)
+}
+
+func (p *TypeParser) noPrefixParseFnError(tok token.Token) {
+	if tok.Type == token.EOF {
+		p.errorf(tok, "unexpected end of input")
+		return
+	}
+	if tok.Type == token.ERROR {
+		p.errorf(tok, "%s", tok.Literal)
+		return
+	}
+	p.errorf(tok, "unexpected %q", tok.Literal)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/lolabyte/tf2go/terraform/ast"
 	"github.com/lolabyte/tf2go/terraform/lexer"
+	"github.com/lolabyte/tf2go/terraform/printer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -167,6 +168,237 @@ func TestParseComplexType(t *testing.T) {
 
 }
 
+func TestParseLegacyType(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		warnings int
+	}{
+		{`"string"`, "string", 1},
+		{`"list"`, "list(any)", 1},
+		{`"map"`, "map(any)", 1},
+		{`list`, "list(any)", 1},
+		{`map`, "map(any)", 1},
+		{`"list(bool)"`, "list(bool)", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			l := lexer.New(tc.input)
+			p := New(l)
+			typeDef := p.ParseType()
+			checkParserErrors(t, p)
+
+			assert.Equal(t, tc.expected, typeDef.String())
+			assert.Len(t, p.Warnings(), tc.warnings)
+		})
+	}
+}
+
+func TestParseLegacyTypeErrors(t *testing.T) {
+	testCases := []struct {
+		input    string
+		errors   []string
+		warnings []string
+	}{
+		{
+			input:    `"list()"`,
+			errors:   []string{},
+			warnings: []string{},
+		},
+		{
+			input:    `"object({a = map()})"`,
+			errors:   []string{},
+			warnings: []string{},
+		},
+		{
+			input:    `"object({a = list(), b = list(string)})"`,
+			errors:   []string{},
+			warnings: []string{},
+		},
+		{
+			input:    `"list(string"`,
+			errors:   []string{"1:12: expected next token to be ), got EOF instead"},
+			warnings: []string{},
+		},
+		{
+			input:    "<<EOT\nstring\nEOT\n",
+			errors:   []string{},
+			warnings: []string{},
+		},
+		{
+			input:    `"string`,
+			errors:   []string{"1:1: unterminated string"},
+			warnings: []string{},
+		},
+		{
+			input:    "<<EOT\nstring\n",
+			errors:   []string{"1:1: unterminated heredoc"},
+			warnings: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(lexer.New(tc.input))
+			assert.NotPanics(t, func() { p.ParseType() })
+
+			assert.Equal(t, tc.errors, p.Errors())
+			assert.Equal(t, tc.warnings, p.Warnings())
+		})
+	}
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		errors   []string
+	}{
+		{
+			input:    "object({ a = , b = string })",
+			expected: "object({a = <bad expression>, b = string})",
+			errors:   []string{`1:14: unexpected ","`},
+		},
+		{
+			input:    "object({ a = @, b = list(%), c = bool })",
+			expected: "object({a = <bad expression>, b = list(<bad expression>), c = bool})",
+			errors:   []string{`1:14: unexpected "@"`, `1:26: unexpected "%"`},
+		},
+		{
+			input:    "list(string number)",
+			expected: "list(string)",
+			errors:   []string{"1:13: expected next token to be ), got NUMBER_TYPE instead"},
+		},
+		{
+			input: `object({
+				a = string
+				b number
+			})`,
+			expected: "object({a = string, b = <bad expression>})",
+			errors:   []string{"3:7: expected next token to be =, got NUMBER_TYPE instead"},
+		},
+		{
+			input:    "map(string))",
+			expected: "map(string)\n<bad expression>",
+			errors:   []string{`1:12: unexpected ")"`},
+		},
+		{
+			input:    "{ a = [1, 2 }",
+			expected: "{a = [1, 2]}",
+			errors:   []string{"1:13: expected next token to be ], got } instead"},
+		},
+		{
+			input:    "optional(",
+			expected: "optional(<bad expression>)",
+			errors:   []string{"1:10: unexpected end of input", "1:10: expected next token to be ), got EOF instead"},
+		},
+		{
+			input:    "object",
+			expected: "object()",
+			errors:   []string{"1:7: expected next token to be (, got EOF instead"},
+		},
+		{
+			input:    "list()",
+			expected: "list()",
+			errors:   []string{},
+		},
+		{
+			input:    "object({ a = map(), b = optional() })",
+			expected: "object({a = map(), b = optional()})",
+			errors:   []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(lexer.New(tc.input))
+			typeDef := p.ParseType()
+
+			assert.Equal(t, tc.errors, p.Errors())
+			assert.Equal(t, tc.expected, printer.Print(typeDef))
+			assert.NotPanics(t, func() { _ = typeDef.String() })
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		errors   []string
+	}{
+		{
+			input:    "-42",
+			expected: "-42",
+		},
+		{
+			input:    "[3.14, -0.5, 1e3, null]",
+			expected: "[3.14, -0.5, 1e3, null]",
+		},
+		{
+			input:    `{ "web server" = { "ip.v4" = "10.0.0.1", port = 80 }, enabled = true }`,
+			expected: `{enabled = true, "web server" = {"ip.v4" = "10.0.0.1", port = 80}}`,
+		},
+		{
+			input:    `{"string": "json", "list": [1, {"null": null}]}`,
+			expected: `{list = [1, {null = null}], string = "json"}`,
+		},
+		{
+			input: `{
+				script = <<-EOT
+				  #!/bin/sh
+				  echo "hi"
+				EOT
+			}`,
+			expected: `{script = "#!/bin/sh\necho \"hi\"\n"}`,
+		},
+		{
+			input:    "[string, list(number), foo]",
+			expected: "[<bad expression>, <bad expression>, <bad expression>]",
+			errors: []string{
+				`1:2: expected a literal value, got "string"`,
+				`1:10: expected a literal value, got "list"`,
+				`1:24: expected a literal value, got "foo"`,
+			},
+		},
+		{
+			input:    "- true",
+			expected: "<bad expression>",
+			errors:   []string{"1:3: expected next token to be NUMBER, got TRUE instead", `1:3: unexpected "true" after value`},
+		},
+		{
+			input:    "1 2",
+			expected: "1",
+			errors:   []string{`1:3: unexpected "2" after value`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			p := New(lexer.New(tc.input))
+			value := p.ParseValue()
+
+			if tc.errors == nil {
+				assert.Empty(t, p.Errors())
+			} else {
+				assert.Equal(t, tc.errors, p.Errors())
+			}
+			assert.Equal(t, tc.expected, printer.Print(value))
+		})
+	}
+
+	t.Run("number values", func(t *testing.T) {
+		value := New(lexer.New("-2.5e2")).ParseValue()
+		lit, ok := value.(*ast.NumberLiteral)
+		if assert.True(t, ok) {
+			assert.Equal(t, "-250", lit.Value.Text('f', -1))
+			_, isInt := lit.Int64()
+			assert.True(t, isInt)
+		}
+	})
+}
+
 func testLiteralExpression(
 	t *testing.T,
 	exp ast.Expression,
@@ -193,8 +425,8 @@ func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
 		return false
 	}
 
-	if integ.Value != value {
-		t.Errorf("integ.Value not %d. got=%d", value, integ.Value)
+	if n, ok := integ.Int64(); !ok || n != value {
+		t.Errorf("integ.Value not %d. got=%s", value, integ.Value)
 		return false
 	}
 
@@ -0,0 +1,204 @@
+package printer
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+)
+
+// Config controls how nodes are printed.
+type Config struct {
+	// Pretty prints objects over multiple lines with their attribute names
+	// aligned, like terraform fmt. Otherwise everything is printed on a
+	// single line.
+	Pretty bool
+
+	// Indent is the indentation used for each nesting level of pretty output.
+	// Defaults to two spaces.
+	Indent string
+}
+
+// Print renders node as compact, canonical HCL. Object attributes are
+// always printed sorted by name, so equal nodes print identically.
+func Print(node ast.Node) string {
+	return (&Config{}).Sprint(node)
+}
+
+// PrettyPrint renders node as canonical HCL spread over multiple lines.
+func PrettyPrint(node ast.Node) string {
+	return (&Config{Pretty: true}).Sprint(node)
+}
+
+// Sprint renders node according to the configuration.
+func (c *Config) Sprint(node ast.Node) string {
+	p := &printer{Config: *c}
+	if p.Indent == "" {
+		p.Indent = "  "
+	}
+
+	return p.render(node, 0)
+}
+
+// Fprint writes node to w according to the configuration.
+func (c *Config) Fprint(w io.Writer, node ast.Node) error {
+	_, err := io.WriteString(w, c.Sprint(node))
+	return err
+}
+
+type printer struct {
+	Config
+}
+
+func (p *printer) render(node ast.Node, depth int) string {
+	switch node := node.(type) {
+	case nil:
+		return ""
+	case *ast.Type:
+		statements := make([]string, 0, len(node.Statements))
+		for _, s := range node.Statements {
+			statements = append(statements, p.render(s, depth))
+		}
+		return strings.Join(statements, "\n")
+	case *ast.ExpressionStatement:
+		if node.Expression == nil {
+			return ""
+		}
+		return p.render(node.Expression, depth)
+	case *ast.KeyValueStatement:
+		return p.renderKey(node.Name) + " = " + p.render(node.Value, depth)
+	case *ast.Identifier:
+		return node.Value
+	case *ast.Bool:
+		return strconv.FormatBool(node.Value)
+	case *ast.NumberLiteral:
+		if node.Token.Literal != "" {
+			return node.Token.Literal
+		}
+		return node.Value.Text('f', -1)
+	case *ast.StringLiteral:
+		return ast.Quote(node.Value)
+	case *ast.NullLiteral:
+		return "null"
+	case *ast.ListLiteral:
+		return p.renderList(node.Elements, depth)
+	case *ast.TupleLiteral:
+		return p.renderList(node.Elements, depth)
+	case *ast.ObjectLiteral:
+		return p.renderObject(node, depth)
+	case *ast.AnyTypeLiteral:
+		return "any"
+	case *ast.BoolTypeLiteral:
+		return "bool"
+	case *ast.NumberTypeLiteral:
+		return "number"
+	case *ast.StringTypeLiteral:
+		return "string"
+	case *ast.ListTypeLiteral:
+		return p.renderCall("list", depth, append([]ast.Expression{node.TypeExpression}, node.ExtraArguments...))
+	case *ast.MapTypeLiteral:
+		return p.renderCall("map", depth, append([]ast.Expression{node.TypeExpression}, node.ExtraArguments...))
+	case *ast.ObjectTypeLiteral:
+		return p.renderCall("object", depth, append([]ast.Expression{node.ObjectSpec}, node.ExtraArguments...))
+	case *ast.OptionalTypeLiteral:
+		args := []ast.Expression{node.TypeExpression}
+		if node.DefaultValue != nil || len(node.ExtraArguments) > 0 {
+			args = append(args, node.DefaultValue)
+		}
+		return p.renderCall("optional", depth, append(args, node.ExtraArguments...))
+	}
+
+	return node.String()
+}
+
+func (p *printer) renderCall(name string, depth int, args []ast.Expression) string {
+	rendered := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == nil && len(args) == 1 {
+			continue
+		}
+		rendered = append(rendered, p.render(arg, depth))
+	}
+
+	return name + "(" + strings.Join(rendered, ", ") + ")"
+}
+
+func (p *printer) renderList(elements []ast.Expression, depth int) string {
+	rendered := make([]string, 0, len(elements))
+	multiline := false
+	for _, el := range elements {
+		s := p.render(el, depth+1)
+		multiline = multiline || strings.Contains(s, "\n")
+		rendered = append(rendered, s)
+	}
+
+	if !p.Pretty || !multiline {
+		return "[" + strings.Join(rendered, ", ") + "]"
+	}
+
+	var out strings.Builder
+	out.WriteString("[\n")
+	for _, s := range rendered {
+		out.WriteString(p.indent(depth + 1))
+		out.WriteString(s)
+		out.WriteString(",\n")
+	}
+	out.WriteString(p.indent(depth))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+func (p *printer) renderObject(obj *ast.ObjectLiteral, depth int) string {
+	pairs := obj.SortedKVPairs()
+	if len(pairs) == 0 {
+		return "{}"
+	}
+
+	keys := make([]string, len(pairs))
+	width := 0
+	for i, kv := range pairs {
+		keys[i] = p.renderKey(kv.Key)
+		if len(keys[i]) > width {
+			width = len(keys[i])
+		}
+	}
+
+	if !p.Pretty {
+		attrs := make([]string, len(pairs))
+		for i, kv := range pairs {
+			attrs[i] = keys[i] + " = " + p.render(kv.Value, depth)
+		}
+		return "{" + strings.Join(attrs, ", ") + "}"
+	}
+
+	var out strings.Builder
+	out.WriteString("{\n")
+	for i, kv := range pairs {
+		out.WriteString(p.indent(depth + 1))
+		out.WriteString(keys[i])
+		out.WriteString(strings.Repeat(" ", width-len(keys[i])))
+		out.WriteString(" = ")
+		out.WriteString(p.render(kv.Value, depth+1))
+		out.WriteString("\n")
+	}
+	out.WriteString(p.indent(depth))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+func (p *printer) renderKey(key ast.Expression) string {
+	switch key := key.(type) {
+	case *ast.Identifier:
+		return key.Value
+	case *ast.StringLiteral:
+		return ast.Quote(key.Value)
+	}
+	return p.render(key, 0)
+}
+
+func (p *printer) indent(depth int) string {
+	return strings.Repeat(p.Indent, depth)
+}
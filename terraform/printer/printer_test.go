@@ -0,0 +1,121 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/lexer"
+	"github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, input string) *ast.Type {
+	p := parser.New(lexer.New(input))
+	typeDef := p.ParseType()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return typeDef
+}
+
+func TestPrint(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"string", "string"},
+		{"list( number )", "list(number)"},
+		{"map(list(any))", "map(list(any))"},
+		{"[1,2,3]", "[1, 2, 3]"},
+		{"[]", "[]"},
+		{"{}", "{}"},
+		{`{ b = "x", a = [true, false] }`, `{a = [true, false], b = "x"}`},
+		{`{ "quoted key" = 1 }`, `{"quoted key" = 1}`},
+		{`["say \"hi\"\n\\ $${var}"]`, `["say \"hi\"\n\\ $${var}"]`},
+		{
+			`object({ name = string, enabled = optional(bool, true), tags = optional(map(string)) })`,
+			`object({enabled = optional(bool, true), name = string, tags = optional(map(string))})`,
+		},
+		{
+			`object({ website = optional(object({ index_document = optional(string, "index.html") }), {}) })`,
+			`object({website = optional(object({index_document = optional(string, "index.html")}), {})})`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Print(parse(t, tc.input)))
+		})
+	}
+}
+
+func TestPrettyPrint(t *testing.T) {
+	input := `list(object({ name = string, secondary_ip_ranges = list(object({ range_name = string, ip_cidr_range = string })), labels = optional(list(object({ key = string })), [{ key = "a" }]) }))`
+
+	expected := `list(object({
+  labels              = optional(list(object({
+    key = string
+  })), [
+    {
+      key = "a"
+    },
+  ])
+  name                = string
+  secondary_ip_ranges = list(object({
+    ip_cidr_range = string
+    range_name    = string
+  }))
+}))`
+
+	assert.Equal(t, expected, PrettyPrint(parse(t, input)))
+
+	cfg := &Config{Pretty: true, Indent: "\t"}
+	assert.Equal(t, "object({\n\ta = string\n})", cfg.Sprint(parse(t, "object({a = string})")))
+}
+
+func TestRoundTrip(t *testing.T) {
+	inputs := []string{
+		"any",
+		"list(string)",
+		"map(list(number))",
+		`{ "a b" = [1, 2], c = { d = null, e = "f" } }`,
+		`["tabs\tand \"quotes\" and %%{ directives }", "\u00e9"]`,
+		`object({
+			name    = string
+			enabled = optional(bool, true)
+			website = optional(object({
+				index_document = optional(string, "index.html")
+				error_document = optional(string)
+			}), {})
+		})`,
+		`list(
+			object({
+				name                     = string
+				ip_cidr_range            = string
+				private_ip_google_access = bool
+				secondary_ip_ranges = list(
+					object({
+						range_name    = string
+						ip_cidr_range = string
+					})
+				)
+			})
+		)`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			original := parse(t, input)
+			canonical := Print(original)
+
+			for _, printed := range []string{canonical, PrettyPrint(original)} {
+				assert.Equal(t, canonical, Print(parse(t, printed)))
+			}
+		})
+	}
+}
+
+func TestPrintListLiteralString(t *testing.T) {
+	// ast String methods must not panic on non-empty lists
+	assert.Equal(t, "[1, 2]", parse(t, "[1, 2]").String())
+}
@@ -0,0 +1,410 @@
+// Package protoconv converts between the types tf2go generates for a module
+// and the protobuf messages generated from the .proto file tf2go writes for
+// it.
+//
+// Fields are matched by name: a Go field tagged with the Terraform name n is
+// copied to and from the message field FieldName(n). Values of the any type
+// and outputs are held in google.protobuf.Value messages, and lists or maps
+// nested directly in lists or maps are held in wrapper messages with a single
+// field named values.
+//
+// Protobuf has no null, so variables set to null are not carried across, and
+// null elements of lists and maps come back as zero values.
+package protoconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ValueMessage is the full name of the message holding values of the any type
+// and outputs.
+const ValueMessage = "google.protobuf.Value"
+
+// WrapperField is the name of the field of the messages wrapping lists or maps
+// nested directly in lists or maps.
+const WrapperField = "values"
+
+// FieldName returns the name of the message field for a variable, output or
+// object attribute, which is its Terraform name with the dashes Terraform
+// allows replaced by underscores.
+func FieldName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// ToProto copies v, a generated type such as Variables or Outputs, into m.
+func ToProto(v interface{}, m proto.Message) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("protoconv: cannot convert %T to a message", v)
+	}
+	return structToMessage(rv, m.ProtoReflect(), "")
+}
+
+// FromProto copies m into v, which must be a pointer to a generated type such
+// as Variables or Outputs.
+func FromProto(m proto.Message, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("protoconv: FromProto needs a non-nil pointer to a struct, got %T", v)
+	}
+	return messageToStruct(m.ProtoReflect(), rv.Elem(), "")
+}
+
+func structToMessage(rv reflect.Value, msg protoreflect.Message, path string) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < rv.NumField(); i++ {
+		name, ok := fieldName(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+
+		fieldPath := joinPath(path, name)
+		fd := fields.ByName(protoreflect.Name(FieldName(name)))
+		if fd == nil {
+			return fmt.Errorf("%s: %s has no field %s", fieldPath, msg.Descriptor().FullName(), FieldName(name))
+		}
+
+		fv := rv.Field(i)
+		if isEmpty(fv) {
+			continue
+		}
+		if err := setField(msg, fd, fv, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, rv reflect.Value, path string) error {
+	rv = indirect(rv)
+
+	switch {
+	case fd.IsList():
+		if rv.Kind() != reflect.Slice {
+			return mismatch(path, rv, fd)
+		}
+		list := msg.Mutable(fd).List()
+		for i := 0; i < rv.Len(); i++ {
+			el, err := toValue(rv.Index(i), fd, list.NewElement, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+			list.Append(el)
+		}
+		return nil
+	case fd.IsMap():
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return mismatch(path, rv, fd)
+		}
+		m := msg.Mutable(fd).Map()
+		for _, k := range sortedKeys(rv) {
+			el, err := toValue(rv.MapIndex(k), fd.MapValue(), m.NewValue, fmt.Sprintf("%s[%q]", path, k.String()))
+			if err != nil {
+				return err
+			}
+			m.Set(protoreflect.ValueOfString(k.String()).MapKey(), el)
+		}
+		return nil
+	}
+
+	val, err := toValue(rv, fd, func() protoreflect.Value { return msg.NewField(fd) }, path)
+	if err != nil {
+		return err
+	}
+	msg.Set(fd, val)
+	return nil
+}
+
+// toValue converts rv into a value of the field, or of the elements of the
+// list field, fd. newMessage returns an empty message to fill for message
+// values.
+func toValue(rv reflect.Value, fd protoreflect.FieldDescriptor, newMessage func() protoreflect.Value, path string) (protoreflect.Value, error) {
+	if fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == ValueMessage {
+		b, err := jsonEncoding(rv)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: %v", path, err)
+		}
+		val := newMessage()
+		if err := protojson.Unmarshal(b, val.Message().Interface()); err != nil {
+			return protoreflect.Value{}, fmt.Errorf("%s: %v", path, err)
+		}
+		return val, nil
+	}
+
+	rv = indirect(rv)
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if rv.Kind() == reflect.Bool {
+			return protoreflect.ValueOfBool(rv.Bool()), nil
+		}
+	case protoreflect.Int64Kind:
+		if rv.Kind() == reflect.Int64 {
+			return protoreflect.ValueOfInt64(rv.Int()), nil
+		}
+	case protoreflect.DoubleKind:
+		if rv.Kind() == reflect.Float64 {
+			return protoreflect.ValueOfFloat64(rv.Float()), nil
+		}
+	case protoreflect.StringKind:
+		if rv.Kind() == reflect.String {
+			return protoreflect.ValueOfString(rv.String()), nil
+		}
+	case protoreflect.MessageKind:
+		val := newMessage()
+		msg := val.Message()
+		switch rv.Kind() {
+		case reflect.Struct:
+			if err := structToMessage(rv, msg, path); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return val, nil
+		case reflect.Slice, reflect.Map:
+			wrapped := msg.Descriptor().Fields().ByName(WrapperField)
+			if wrapped == nil {
+				break
+			}
+			if err := setField(msg, wrapped, rv, path); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return val, nil
+		}
+	}
+
+	return protoreflect.Value{}, mismatch(path, rv, fd)
+}
+
+func messageToStruct(msg protoreflect.Message, rv reflect.Value, path string) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < rv.NumField(); i++ {
+		name, ok := fieldName(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+
+		fieldPath := joinPath(path, name)
+		fd := fields.ByName(protoreflect.Name(FieldName(name)))
+		if fd == nil {
+			return fmt.Errorf("%s: %s has no field %s", fieldPath, msg.Descriptor().FullName(), FieldName(name))
+		}
+
+		fv := rv.Field(i)
+		fv.Set(reflect.Zero(fv.Type()))
+		if !msg.Has(fd) {
+			continue
+		}
+		if err := getField(msg.Get(fd), fd, fv, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getField(val protoreflect.Value, fd protoreflect.FieldDescriptor, dst reflect.Value, path string) error {
+	switch {
+	case fd.IsList():
+		if dst.Kind() != reflect.Slice {
+			return mismatch(path, dst, fd)
+		}
+		list := val.List()
+		s := reflect.MakeSlice(dst.Type(), list.Len(), list.Len())
+		for i := 0; i < list.Len(); i++ {
+			if err := fromValue(list.Get(i), fd, s.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+	case fd.IsMap():
+		if dst.Kind() != reflect.Map || dst.Type().Key().Kind() != reflect.String {
+			return mismatch(path, dst, fd)
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), val.Map().Len())
+		var err error
+		val.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			el := reflect.New(dst.Type().Elem()).Elem()
+			err = fromValue(v, fd.MapValue(), el, fmt.Sprintf("%s[%q]", path, k.String()))
+			m.SetMapIndex(reflect.ValueOf(k.String()).Convert(dst.Type().Key()), el)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		dst.Set(m)
+		return nil
+	}
+
+	return fromValue(val, fd, dst, path)
+}
+
+// fromValue converts val, a value of the field or of the elements of the list
+// field fd, into dst.
+func fromValue(val protoreflect.Value, fd protoreflect.FieldDescriptor, dst reflect.Value, path string) error {
+	if fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == ValueMessage {
+		b, err := protojson.Marshal(val.Message().Interface())
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		return setJSONEncoding(dst, b, path)
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		p := reflect.New(dst.Type().Elem())
+		if err := fromValue(val, fd, p.Elem(), path); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if dst.Kind() == reflect.Bool {
+			dst.SetBool(val.Bool())
+			return nil
+		}
+	case protoreflect.Int64Kind:
+		if dst.Kind() == reflect.Int64 {
+			dst.SetInt(val.Int())
+			return nil
+		}
+	case protoreflect.DoubleKind:
+		if dst.Kind() == reflect.Float64 {
+			dst.SetFloat(val.Float())
+			return nil
+		}
+	case protoreflect.StringKind:
+		if dst.Kind() == reflect.String {
+			dst.SetString(val.String())
+			return nil
+		}
+	case protoreflect.MessageKind:
+		msg := val.Message()
+		switch dst.Kind() {
+		case reflect.Struct:
+			return messageToStruct(msg, dst, path)
+		case reflect.Slice, reflect.Map:
+			wrapped := msg.Descriptor().Fields().ByName(WrapperField)
+			if wrapped == nil {
+				break
+			}
+			return getField(msg.Get(wrapped), wrapped, dst, path)
+		}
+	}
+
+	return mismatch(path, dst, fd)
+}
+
+// jsonEncoding returns the JSON encoding of rv, taking byte slices such as
+// json.RawMessage and terraform.Dynamic to hold one already.
+func jsonEncoding(rv reflect.Value) ([]byte, error) {
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		if rv.Len() == 0 {
+			return []byte("null"), nil
+		}
+		return rv.Bytes(), nil
+	}
+	if !rv.IsValid() || (rv.Kind() == reflect.Interface && rv.IsNil()) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(rv.Interface())
+}
+
+// setJSONEncoding sets dst to the value with the JSON encoding b.
+func setJSONEncoding(dst reflect.Value, b []byte, path string) error {
+	// Decode first, so that byte slices hold a compact encoding with
+	// sorted keys whatever protojson produced
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	switch {
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		dst.SetBytes(b)
+	case dst.Kind() == reflect.Interface:
+		if v != nil {
+			dst.Set(reflect.ValueOf(v))
+		}
+	default:
+		if err := json.Unmarshal(b, dst.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+func mismatch(path string, rv reflect.Value, fd protoreflect.FieldDescriptor) error {
+	kind := fd.Kind().String()
+	if fd.Kind() == protoreflect.MessageKind {
+		kind = string(fd.Message().FullName())
+	}
+	return fmt.Errorf("%s: cannot convert between %s and %s", path, rv.Type(), kind)
+}
+
+// fieldName returns the Terraform name of a generated struct field, from its
+// tf2go tag or otherwise its json tag.
+func fieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag, ok := field.Tag.Lookup("tf2go")
+	if !ok {
+		tag, ok = field.Tag.Lookup("json")
+	}
+	name := strings.Split(tag, ",")[0]
+	if !ok || name == "" || name == "-" {
+		return "", false
+	}
+	return name, true
+}
+
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// indirect follows pointers, taking nil pointers to point to the zero value.
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type().Elem())
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func sortedKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,194 @@
+package protoconv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+)
+
+type testServer struct {
+	Name   string      `json:"name,omitempty" tf2go:"name,required"`
+	Ports  []int64     `json:"ports,omitempty" tf2go:"ports,required"`
+	Weight *int64      `json:"weight,omitempty" tf2go:"weight"`
+	Ratio  *float64    `json:"ratio,omitempty" tf2go:"ratio"`
+	Extra  interface{} `json:"extra,omitempty" tf2go:"extra"`
+}
+
+type testVariables struct {
+	Enabled *bool                  `json:"enabled,omitempty" tf2go:"enabled"`
+	Matrix  [][]int64              `json:"matrix,omitempty" tf2go:"matrix"`
+	MyName  string                 `json:"my-name,omitempty" tf2go:"my-name"`
+	Servers map[string]*testServer `json:"servers,omitempty" tf2go:"servers"`
+	Untyped json.RawMessage        `json:"untyped,omitempty" tf2go:"untyped"`
+
+	nulls map[string]bool
+}
+
+// testMessages returns the descriptors of the messages tf2go generates for
+// testVariables.
+func testMessages(t *testing.T) protoreflect.MessageDescriptors {
+	field := func(name string, number int32, label descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	// proto3 optional fields are in a oneof of their own, which gives them
+	// presence
+	presence := func(f *descriptorpb.FieldDescriptorProto, oneof int32) *descriptorpb.FieldDescriptorProto {
+		f.Proto3Optional = proto.Bool(true)
+		f.OneofIndex = proto.Int32(oneof)
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	message := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	int64Type := descriptorpb.FieldDescriptorProto_TYPE_INT64
+	stringType := descriptorpb.FieldDescriptorProto_TYPE_STRING
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/struct.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Variables"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					presence(field("enabled", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""), 0),
+					field("matrix", 2, repeated, message, ".test.ListOfInt64"),
+					field("my_name", 3, optional, stringType, ""),
+					field("servers", 4, repeated, message, ".test.Variables.ServersEntry"),
+					field("untyped", 5, optional, message, ".google.protobuf.Value"),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_enabled")}},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("ServersEntry"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							field("key", 1, optional, stringType, ""),
+							field("value", 2, optional, message, ".test.Server"),
+						},
+						Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					},
+				},
+			},
+			{
+				Name: proto.String("Server"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, optional, stringType, ""),
+					field("ports", 2, repeated, int64Type, ""),
+					presence(field("weight", 3, optional, int64Type, ""), 0),
+					field("extra", 4, optional, message, ".google.protobuf.Value"),
+					presence(field("ratio", 5, optional, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""), 1),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_weight")}, {Name: proto.String("_ratio")}},
+			},
+			{
+				Name: proto.String("ListOfInt64"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("values", 1, repeated, int64Type, ""),
+				},
+			},
+		},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages()
+}
+
+func TestRoundTrip(t *testing.T) {
+	messages := testMessages(t)
+
+	enabled := false
+	weight := int64(2)
+	ratio := 0.25
+	v := testVariables{
+		Enabled: &enabled,
+		Matrix:  [][]int64{{1, 2}, {}, {3}},
+		MyName:  "web",
+		Servers: map[string]*testServer{
+			"a": {Name: "a", Ports: []int64{80}, Weight: &weight, Ratio: &ratio, Extra: map[string]interface{}{"ratio": 0.5, "tags": []interface{}{"x", nil}}},
+			"b": {Name: "b"},
+		},
+		Untyped: json.RawMessage(`{"b": [true], "a": null}`),
+	}
+
+	m := dynamicpb.NewMessage(messages.ByName("Variables"))
+	err := ToProto(v, m)
+	assert.NoError(t, err)
+
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"enabled": false,
+		"matrix": [{"values": ["1", "2"]}, {}, {"values": ["3"]}],
+		"my_name": "web",
+		"servers": {
+			"a": {"name": "a", "ports": ["80"], "weight": "2", "ratio": 0.25, "extra": {"ratio": 0.5, "tags": ["x", null]}},
+			"b": {"name": "b"}
+		},
+		"untyped": {"a": null, "b": [true]}
+	}`, string(b))
+
+	var back testVariables
+	err = FromProto(m, &back)
+	assert.NoError(t, err)
+
+	v.Untyped = json.RawMessage(`{"a":null,"b":[true]}`)
+	assert.Equal(t, v, back)
+}
+
+func TestUnsetFields(t *testing.T) {
+	messages := testMessages(t)
+
+	m := dynamicpb.NewMessage(messages.ByName("Variables"))
+	err := ToProto(testVariables{}, m)
+	assert.NoError(t, err)
+
+	b, err := protojson.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(b))
+
+	back := testVariables{MyName: "stale"}
+	err = FromProto(m, &back)
+	assert.NoError(t, err)
+	assert.Equal(t, testVariables{}, back)
+}
+
+func TestMismatch(t *testing.T) {
+	messages := testMessages(t)
+
+	type wrongVariables struct {
+		Zone    string `tf2go:"zone"`
+		Servers []int  `tf2go:"servers"`
+	}
+	err := ToProto(wrongVariables{}, dynamicpb.NewMessage(messages.ByName("Variables")))
+	assert.EqualError(t, err, "zone: test.Variables has no field zone")
+
+	type wrongServers struct {
+		Servers map[string]string `tf2go:"servers"`
+	}
+	err = ToProto(wrongServers{Servers: map[string]string{"a": "b"}}, dynamicpb.NewMessage(messages.ByName("Variables")))
+	assert.EqualError(t, err, `servers["a"]: cannot convert between string and test.Server`)
+
+	err = FromProto(dynamicpb.NewMessage(messages.ByName("Variables")), testVariables{})
+	assert.EqualError(t, err, "protoconv: FromProto needs a non-nil pointer to a struct, got protoconv.testVariables")
+}
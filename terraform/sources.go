@@ -0,0 +1,322 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Source is a layer of variable values, such as the module defaults, a
+// tfvars file, environment variables or the values of a single request.
+type Source struct {
+	// Name identifies the source to operators, e.g. "defaults" or the path
+	// of a tfvars file.
+	Name string
+
+	// Values holds the JSON encoding of the value of each variable the
+	// source sets, keyed by variable name. Null sets the variable to null.
+	Values map[string]json.RawMessage
+}
+
+// ValueSource returns a Source holding the variables set in v, which is
+// usually a generated Variables value such as DefaultVariables(). Variables
+// omitted from its JSON encoding aren't set by the source.
+func ValueSource(name string, v interface{}) (Source, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return Source{}, fmt.Errorf("%s: %v", name, err)
+	}
+
+	s := Source{Name: name}
+	if err := json.Unmarshal(b, &s.Values); err != nil {
+		return Source{}, fmt.Errorf("%s: %v", name, err)
+	}
+	return s, nil
+}
+
+// TFVarsSource returns a Source holding the variables defined in a tfvars
+// file, as parsed by ParseTFVars. name is used as the filename.
+func TFVarsSource(name string, src []byte, types map[string]string) (Source, error) {
+	values, err := ParseTFVars(src, name, types)
+	if err != nil {
+		return Source{}, err
+	}
+	return Source{Name: name, Values: values}, nil
+}
+
+// EnvSource returns a Source holding the variables set by TF_VAR_ environment
+// variables in environ, which is in the form returned by os.Environ. As with
+// Terraform, the values of variables of primitive types and any are taken as
+// literal strings, while others are parsed as HCL expressions, and
+// environment variables for unknown variables are ignored.
+func EnvSource(environ []string, types map[string]string) (Source, error) {
+	s := Source{Name: "environment", Values: make(map[string]json.RawMessage)}
+
+	var errs []string
+	for _, kv := range environ {
+		key, raw, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, "TF_VAR_") {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, "TF_VAR_")
+		typeExpr, ok := types[name]
+		if !ok {
+			continue
+		}
+
+		val, err := envValue(key, raw, typeExpr)
+		if err == nil {
+			s.Values[name], err = convertVariable(name, val, typeExpr)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return Source{}, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return s, nil
+}
+
+// envValue parses the value of an environment variable for a variable of the
+// type given by typeExpr.
+func envValue(key, raw, typeExpr string) (cty.Value, error) {
+	ty, _, err := parseTypeExpression(typeExpr)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if ty.IsPrimitiveType() || ty == cty.DynamicPseudoType {
+		return cty.StringVal(raw), nil
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(raw), key, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	return val, nil
+}
+
+// Provenance records the name of the source that supplied each value of
+// merged variables, keyed by attribute path such as server.ports or
+// tags["env"], in the same form as Change paths.
+type Provenance map[string]string
+
+// Source returns the name of the source that supplied the value at path. A
+// value that wasn't supplied by a source on its own, such as an attribute of
+// an object, comes from the source of the closest enclosing value. Source
+// returns false when no source supplied the value.
+func (p Provenance) Source(path string) (string, bool) {
+	for {
+		if source, ok := p[path]; ok {
+			return source, true
+		}
+
+		i := lastStep(path)
+		if i <= 0 {
+			return "", false
+		}
+		path = path[:i]
+	}
+}
+
+// Paths returns the paths recorded in p, sorted.
+func (p Provenance) Paths() []string {
+	paths := make([]string, 0, len(p))
+	for path := range p {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// set records that source supplied the whole value at path, replacing the
+// sources of any values within it.
+func (p Provenance) set(path, source string) {
+	for existing := range p {
+		if len(existing) > len(path) && strings.HasPrefix(existing, path) && strings.ContainsRune(".[", rune(existing[len(path)])) {
+			delete(p, existing)
+		}
+	}
+	p[path] = source
+}
+
+// lastStep returns the index at which the last step of path starts, which is
+// either a "." or a "[", or -1 when path has a single step.
+func lastStep(path string) int {
+	last := -1
+	inString := false
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case !inString && (c == '.' || c == '['):
+			last = i
+		}
+	}
+	return last
+}
+
+// Merge merges sources, in order, into a value of type T, usually a
+// generated Variables, and records which source supplied each value. Later
+// sources take precedence. Objects and maps are merged attribute by
+// attribute and key by key, ignoring null attributes, while any other value,
+// including lists and values of the any type, is replaced as a whole. A
+// value for a variable T has no field for is an error.
+//
+// Variables that are null once merged are set with SetNull when T has that
+// method, which returns an error for those that can't be null, and the
+// result is checked with its Validate method when it has one.
+func Merge[T any](sources ...Source) (T, Provenance, error) {
+	var v T
+	t := reflect.TypeOf(&v).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	merged := make(map[string]json.RawMessage)
+	prov := make(Provenance)
+	for _, source := range sources {
+		names := make([]string, 0, len(source.Values))
+		for name := range source.Values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			ft := fieldType(t, name)
+			if ft == nil && t.Kind() == reflect.Struct {
+				return v, nil, fmt.Errorf("%s: unknown variable %q", source.Name, name)
+			}
+			value, err := mergeValue(merged[name], source.Values[name], ft, name, source.Name, prov)
+			if err != nil {
+				return v, nil, fmt.Errorf("%s: %s: %v", source.Name, name, err)
+			}
+			merged[name] = value
+		}
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return v, nil, err
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return v, nil, err
+	}
+
+	if nv, ok := interface{}(&v).(interface{ SetNull(name string) error }); ok {
+		names := make([]string, 0, len(merged))
+		for name := range merged {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !isNull(merged[name]) {
+				continue
+			}
+			if err := nv.SetNull(name); err != nil {
+				return v, nil, err
+			}
+		}
+	}
+
+	if vv, ok := interface{}(v).(interface{ Validate() error }); ok {
+		if err := vv.Validate(); err != nil {
+			return v, nil, err
+		}
+	}
+
+	return v, prov, nil
+}
+
+// mergeValue merges src over dst, which are the JSON encodings of values of
+// the Go type t at path, and records the values supplied by source in prov.
+func mergeValue(dst, src json.RawMessage, t reflect.Type, path, source string, prov Provenance) (json.RawMessage, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	mergeable := t != nil && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map)
+	if !mergeable || !isObject(dst) || !isObject(src) {
+		prov.set(path, source)
+		return src, nil
+	}
+
+	var dstAttrs, srcAttrs map[string]json.RawMessage
+	if err := json.Unmarshal(dst, &dstAttrs); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(src, &srcAttrs); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(srcAttrs))
+	for key := range srcAttrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		// Unset optional attributes are null once converted to their type
+		if isNull(srcAttrs[key]) {
+			continue
+		}
+
+		var elemType reflect.Type
+		var elemPath string
+		if t.Kind() == reflect.Struct {
+			elemType, elemPath = fieldType(t, key), path+"."+key
+		} else {
+			elemType, elemPath = t.Elem(), fmt.Sprintf("%s[%q]", path, key)
+		}
+
+		value, err := mergeValue(dstAttrs[key], srcAttrs[key], elemType, elemPath, source, prov)
+		if err != nil {
+			return nil, err
+		}
+		dstAttrs[key] = value
+	}
+
+	return json.Marshal(dstAttrs)
+}
+
+// fieldType returns the type of the field of the struct type t named name by
+// its tf2go or json tag, or nil when there is none.
+func fieldType(t reflect.Type, name string) reflect.Type {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() {
+			if fieldName, _ := fieldName(field); fieldName == name {
+				return field.Type
+			}
+		}
+	}
+	return nil
+}
+
+func isObject(b json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("{"))
+}
+
+func isNull(b json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(b), []byte("null"))
+}
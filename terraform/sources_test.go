@@ -0,0 +1,200 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mergeVariables struct {
+	Region string            `json:"region,omitempty" tf2go:"region"`
+	Ports  []int64           `json:"ports,omitempty" tf2go:"ports"`
+	Server *testServer       `json:"server,omitempty" tf2go:"server"`
+	Tags   map[string]string `json:"tags,omitempty" tf2go:"tags"`
+	Extra  interface{}       `json:"extra,omitempty" tf2go:"extra"`
+
+	nulls map[string]bool
+}
+
+func (v *mergeVariables) SetNull(name string) error {
+	if _, ok := mergeVariableTypes[name]; !ok {
+		return fmt.Errorf("unknown variable %q", name)
+	}
+	if name == "tags" {
+		return fmt.Errorf("variable %q is not nullable", name)
+	}
+	if v.nulls == nil {
+		v.nulls = make(map[string]bool)
+	}
+	v.nulls[name] = true
+	return nil
+}
+
+func (v mergeVariables) MarshalJSON() ([]byte, error) {
+	type variables mergeVariables
+	b, err := json.Marshal(variables(v))
+	if err != nil || len(v.nulls) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name := range v.nulls {
+		fields[name] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
+}
+
+var mergeVariableTypes = map[string]string{
+	"region": "string",
+	"ports":  "list(number)",
+	"server": "object({name = string, ports = list(number), labels = optional(map(string)), weight = optional(number)})",
+	"tags":   "map(string)",
+	"extra":  "any",
+}
+
+func TestMerge(t *testing.T) {
+	weight := int64(1)
+	defaults, err := ValueSource("defaults", mergeVariables{
+		Region: "eu-west-1",
+		Ports:  []int64{80, 443},
+		Server: &testServer{Name: "web", Ports: []int64{80}, Labels: map[string]string{"tier": "frontend"}, Weight: &weight},
+		Tags:   map[string]string{"team": "platform"},
+		Extra:  map[string]interface{}{"a": 1},
+	})
+	assert.NoError(t, err)
+
+	file, err := TFVarsSource("prod.tfvars", []byte(`
+ports  = [8080]
+server = { name = "api", ports = [8080] }
+tags   = { env = "prod" }
+extra  = { b = 2 }
+`), mergeVariableTypes)
+	assert.NoError(t, err)
+
+	env, err := EnvSource([]string{
+		"HOME=/root",
+		"TF_VAR_region=us-east-1",
+		`TF_VAR_tags={ env = "staging", "cost.center" = "42" }`,
+		"TF_VAR_unknown=1",
+	}, mergeVariableTypes)
+	assert.NoError(t, err)
+
+	request, err := ValueSource("request", mergeVariables{
+		Server: &testServer{Labels: map[string]string{"tier": "backend"}},
+	})
+	assert.NoError(t, err)
+
+	v, prov, err := Merge[mergeVariables](defaults, file, env, request)
+	assert.NoError(t, err)
+
+	assert.Equal(t, mergeVariables{
+		Region: "us-east-1",
+		Ports:  []int64{8080},
+		Server: &testServer{Name: "api", Ports: []int64{8080}, Labels: map[string]string{"tier": "backend"}, Weight: &weight},
+		Tags:   map[string]string{"team": "platform", "env": "staging", "cost.center": "42"},
+		Extra:  map[string]interface{}{"b": float64(2)},
+	}, v)
+
+	assert.Equal(t, []string{
+		"extra",
+		"ports",
+		"region",
+		"server",
+		`server.labels["tier"]`,
+		"server.name",
+		"server.ports",
+		"tags",
+		`tags["cost.center"]`,
+		`tags["env"]`,
+	}, prov.Paths())
+
+	for path, expected := range map[string]string{
+		"region":                "environment",
+		"ports":                 "prod.tfvars",
+		"ports[0]":              "prod.tfvars",
+		"server.weight":         "defaults",
+		`server.labels["tier"]`: "request",
+		`tags["team"]`:          "defaults",
+		`tags["cost.center"]`:   "environment",
+		"extra":                 "prod.tfvars",
+	} {
+		source, ok := prov.Source(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, expected, source, path)
+	}
+
+	_, ok := prov.Source("unset")
+	assert.False(t, ok)
+}
+
+func TestMergeReplacesValuesWithin(t *testing.T) {
+	defaults, err := ValueSource("defaults", mergeVariables{
+		Server: &testServer{Name: "web", Labels: map[string]string{"tier": "frontend"}},
+	})
+	assert.NoError(t, err)
+
+	request, err := ValueSource("request", mergeVariables{
+		Server: &testServer{Labels: map[string]string{"tier": "backend"}},
+	})
+	assert.NoError(t, err)
+
+	override := Source{Name: "override", Values: map[string]json.RawMessage{"server": json.RawMessage(`null`)}}
+
+	v, prov, err := Merge[mergeVariables](defaults, request, override)
+	assert.NoError(t, err)
+	assert.Nil(t, v.Server)
+	assert.Equal(t, map[string]bool{"server": true}, v.nulls)
+	assert.Equal(t, Provenance{"server": "override"}, prov)
+}
+
+func TestMergeNull(t *testing.T) {
+	defaults, err := ValueSource("defaults", mergeVariables{Region: "eu-west-1", Tags: map[string]string{"team": "platform"}})
+	assert.NoError(t, err)
+
+	file, err := TFVarsSource("prod.tfvars", []byte(`region = null`), mergeVariableTypes)
+	assert.NoError(t, err)
+
+	v, prov, err := Merge[mergeVariables](defaults, file)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"region": true}, v.nulls)
+	assert.Equal(t, "prod.tfvars", prov["region"])
+
+	file, err = TFVarsSource("prod.tfvars", []byte(`tags = null`), mergeVariableTypes)
+	assert.NoError(t, err)
+
+	_, _, err = Merge[mergeVariables](defaults, file)
+	assert.EqualError(t, err, `variable "tags" is not nullable`)
+}
+
+func TestMergeUnknownVariable(t *testing.T) {
+	request := Source{Name: "request", Values: map[string]json.RawMessage{"zone": json.RawMessage(`"a"`)}}
+
+	_, _, err := Merge[mergeVariables](request)
+	assert.EqualError(t, err, `request: unknown variable "zone"`)
+}
+
+func TestEnvSource(t *testing.T) {
+	_, err := EnvSource([]string{
+		"TF_VAR_ports=80",
+		"TF_VAR_server={",
+	}, mergeVariableTypes)
+	assert.EqualError(t, err, `TF_VAR_ports: invalid value for variable "ports": list of number required
+TF_VAR_server: TF_VAR_server:1,2-2: Missing expression; Expected the start of an expression, but found the end of the file.`)
+
+	s, err := EnvSource([]string{"TF_VAR_region=eu-west-1", "TF_VAR_extra=[1, 2]"}, mergeVariableTypes)
+	assert.NoError(t, err)
+
+	var names []string
+	for name := range s.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"extra", "region"}, names)
+	assert.Equal(t, `"[1, 2]"`, string(s.Values["extra"]))
+}
@@ -0,0 +1,190 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	tfLexer "github.com/lolabyte/tf2go/terraform/lexer"
+	tfParser "github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ParseTFVars parses variable definitions in HCL (.tfvars) or JSON
+// (.tfvars.json) syntax, chosen by the extension of filename or, when
+// filename is empty, detected from src. types holds the type expression of
+// every variable of the module, keyed by name.
+//
+// Each value is converted to the type of its variable, with the defaults of
+// optional object attributes applied, and returned as its JSON encoding.
+// Variables set to null are returned as null. Every unknown variable and
+// invalid value is reported at once.
+func ParseTFVars(src []byte, filename string, types map[string]string) (map[string]json.RawMessage, error) {
+	parser := hclparse.NewParser()
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	switch {
+	case strings.HasSuffix(filename, ".json"):
+		file, diags = parser.ParseJSON(src, filename)
+	case filename == "" && bytes.HasPrefix(bytes.TrimSpace(src), []byte("{")):
+		file, diags = parser.ParseJSON(src, "<tfvars>")
+	case filename == "":
+		file, diags = parser.ParseHCL(src, "<tfvars>")
+	default:
+		file, diags = parser.ParseHCL(src, filename)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]json.RawMessage, len(attrs))
+	var errs []string
+	for _, name := range names {
+		attr := attrs[name]
+
+		typeExpr, ok := types[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: unknown variable %q", attr.NameRange, name))
+			continue
+		}
+
+		val, valDiags := attr.Expr.Value(nil)
+		if valDiags.HasErrors() {
+			errs = append(errs, valDiags.Error())
+			continue
+		}
+
+		b, err := convertVariable(name, val, typeExpr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", attr.Expr.Range(), err))
+			continue
+		}
+		values[name] = b
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return values, nil
+}
+
+// convertVariable converts val to the type given by typeExpr, applying any
+// defaults, and returns its JSON encoding.
+func convertVariable(name string, val cty.Value, typeExpr string) (json.RawMessage, error) {
+	ty, defaults, err := parseTypeExpression(typeExpr)
+	if err != nil {
+		return nil, fmt.Errorf("variable %q: %v", name, err)
+	}
+
+	if defaults != nil {
+		val = defaults.Apply(val)
+	}
+	val, err = convert.Convert(val, ty)
+	if pathErr, ok := err.(cty.PathError); ok && len(pathErr.Path) > 0 {
+		return nil, fmt.Errorf("invalid value for %s: %v", formatPath(name, pathErr.Path), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for variable %q: %v", name, err)
+	}
+
+	if val.IsNull() {
+		return json.RawMessage("null"), nil
+	}
+	// The converted value's own type, so that values of the any type aren't
+	// wrapped with their type
+	return ctyjson.Marshal(val, val.Type())
+}
+
+// parseTypeExpression parses a type expression into its cty type and
+// defaults.
+func parseTypeExpression(typeExpr string) (cty.Type, *typeexpr.Defaults, error) {
+	parser := tfParser.New(tfLexer.New(typeExpr))
+	t := parser.ParseType()
+	if errs := parser.Errors(); len(errs) > 0 {
+		return cty.NilType, nil, fmt.Errorf("invalid type %s: %s", typeExpr, strings.Join(errs, "; "))
+	}
+
+	return CtyType(t)
+}
+
+// formatPath formats a cty path within the variable name as a Terraform
+// attribute path, such as ports[1], server.name or tags["env"].
+func formatPath(name string, path cty.Path) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			sb.WriteString(".")
+			sb.WriteString(step.Name)
+		case cty.IndexStep:
+			if step.Key.Type() == cty.String {
+				fmt.Fprintf(&sb, "[%q]", step.Key.AsString())
+			} else {
+				fmt.Fprintf(&sb, "[%s]", step.Key.AsBigFloat().Text('f', -1))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// FormatTFVars formats variable definitions encoded as a JSON object, such as
+// those written by WriteTFVarJSON, as an HCL .tfvars file.
+func FormatTFVars(src []byte) ([]byte, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(src, &values); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f := hclwrite.NewEmptyFile()
+	for _, name := range names {
+		val, err := ctyValueFromJSON(values[name])
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: %v", name, err)
+		}
+		f.Body().SetAttributeValue(name, val)
+	}
+
+	return hclwrite.Format(f.Bytes()), nil
+}
+
+// ctyValueFromJSON decodes a JSON value into the cty value of the type it
+// implies, where arrays are tuples and objects are objects.
+func ctyValueFromJSON(b json.RawMessage) (cty.Value, error) {
+	if isNull(b) {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
+
+	ty, err := ctyjson.ImpliedType(b)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(b, ty)
+}
@@ -0,0 +1,129 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testVariableTypes = map[string]string{
+	"region": "string",
+	"ports":  "list(number)",
+	"server": "object({name = string, weight = optional(number, 1)})",
+	"extra":  "any",
+}
+
+func TestParseTFVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		filename string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name: "HCL",
+			src: `
+region = "eu-west-1" # comment
+ports  = ["80", 443]
+server = {
+  name = "web"
+}
+extra = { tags = ["a"] }
+`,
+			filename: "terraform.tfvars",
+			expected: map[string]string{
+				"region": `"eu-west-1"`,
+				"ports":  `[80,443]`,
+				"server": `{"name":"web","weight":1}`,
+				"extra":  `{"tags":["a"]}`,
+			},
+		},
+		{
+			name:     "JSON",
+			src:      `{"region": "eu-west-1", "server": {"name": "web", "weight": 2}}`,
+			filename: "terraform.tfvars.json",
+			expected: map[string]string{
+				"region": `"eu-west-1"`,
+				"server": `{"name":"web","weight":2}`,
+			},
+		},
+		{
+			name:     "detects JSON without a filename",
+			src:      ` {"ports": [80]}`,
+			expected: map[string]string{"ports": `[80]`},
+		},
+		{
+			name:     "null",
+			src:      `region = null`,
+			expected: map[string]string{"region": `null`},
+		},
+		{
+			name: "unknown variables and invalid values",
+			src: `region = "eu-west-1"
+zone   = "a"
+ports  = ["http"]
+server = {}
+`,
+			filename: "prod.tfvars",
+			err: `prod.tfvars:3,10-18: invalid value for ports[0]: a number is required
+prod.tfvars:4,10-12: invalid value for variable "server": attribute "name" is required
+prod.tfvars:2,1-5: unknown variable "zone"`,
+		},
+		{
+			name: "syntax errors",
+			src:  `region = `,
+			err:  `<tfvars>:1,10-10: Missing expression; Expected the start of an expression, but found the end of the file.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := ParseTFVars([]byte(tt.src), tt.filename, testVariableTypes)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			actual := make(map[string]string)
+			for name, value := range values {
+				actual[name] = string(value)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestFormatTFVars(t *testing.T) {
+	src, err := json.Marshal(map[string]interface{}{
+		"region": "eu-west-1",
+		"ports":  []int{80, 443},
+		"server": map[string]interface{}{"name": "web", "labels": map[string]string{"app.kubernetes.io/name": "web"}},
+		"extra":  nil,
+	})
+	assert.NoError(t, err)
+
+	b, err := FormatTFVars(src)
+	assert.NoError(t, err)
+	assert.Equal(t, `extra  = null
+ports  = [80, 443]
+region = "eu-west-1"
+server = {
+  labels = {
+    "app.kubernetes.io/name" = "web"
+  }
+  name = "web"
+}
+`, string(b))
+
+	values, err := ParseTFVars(b, "terraform.tfvars", map[string]string{
+		"region": "string",
+		"ports":  "list(number)",
+		"server": "object({name = string, labels = map(string)})",
+		"extra":  "any",
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"web","labels":{"app.kubernetes.io/name":"web"}}`, string(values["server"]))
+}
@@ -6,6 +6,10 @@ const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
 
+	// ERROR is input that can't be lexed as a token, such as a string
+	// without its closing quote, with the problem as its literal
+	ERROR = "ERROR"
+
 	// Identifiers
 	IDENT = "IDENT"
 
@@ -14,6 +18,10 @@ const (
 	NUMBER = "NUMBER"
 	STRING = "STRING"
 
+	// HEREDOC is a string written as a heredoc, which unlike a quoted
+	// STRING is never a Terraform 0.11 quoted type
+	HEREDOC = "HEREDOC"
+
 	// Keywords
 	NULL  = "NULL"
 	FALSE = "FALSE"
@@ -34,6 +42,8 @@ const (
 
 	// Operators
 	ASSIGN = "="
+	COLON  = ":" // alternative to ASSIGN in object literals
+	MINUS  = "-"
 
 	// Delimiters
 	COMMA = ","
@@ -54,6 +64,10 @@ const (
 type Token struct {
 	Type    TokenType
 	Literal string
+
+	// Position of the token's first character in the input, starting at 1
+	Line   int
+	Column int
 }
 
 var keywords = map[string]TokenType{
@@ -77,3 +91,10 @@ func LookupIdent(ident string) TokenType {
 	}
 	return IDENT
 }
+
+// IsKeyword reports whether ident is one of the reserved type or literal
+// keywords.
+func IsKeyword(ident string) bool {
+	_, ok := keywords[ident]
+	return ok
+}
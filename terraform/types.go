@@ -0,0 +1,417 @@
+package terraform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/token"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// CtyType converts a parsed type expression into the equivalent cty type.
+// The defaults of any optional object attributes are returned in the same
+// form as HCL's typeexpr.TypeConstraintWithDefaults, so they can be applied
+// with Defaults.Apply, and are nil when the type has none.
+//
+// The type expression is expected to have passed checker.Check.
+func CtyType(t *ast.Type) (cty.Type, *typeexpr.Defaults, error) {
+	if len(t.Statements) != 1 {
+		return cty.NilType, nil, fmt.Errorf("expected a single type expression, got %d", len(t.Statements))
+	}
+
+	es, ok := t.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return cty.NilType, nil, fmt.Errorf("unexpected statement %s", t.Statements[0].String())
+	}
+
+	return CtyTypeOf(es.Expression)
+}
+
+// CtyTypeOf converts a single type expression, such as the type of an object
+// attribute, into the equivalent cty type and defaults.
+func CtyTypeOf(exp ast.Expression) (cty.Type, *typeexpr.Defaults, error) {
+	switch exp := exp.(type) {
+	case *ast.AnyTypeLiteral:
+		return cty.DynamicPseudoType, nil, nil
+	case *ast.BoolTypeLiteral:
+		return cty.Bool, nil, nil
+	case *ast.NumberTypeLiteral:
+		return cty.Number, nil, nil
+	case *ast.StringTypeLiteral:
+		return cty.String, nil, nil
+	case *ast.ListTypeLiteral:
+		ety, defaults, err := CtyTypeOf(exp.TypeExpression)
+		if err != nil {
+			return cty.NilType, nil, err
+		}
+		ty := cty.List(ety)
+		return ty, collectionDefaults(ty, defaults), nil
+	case *ast.MapTypeLiteral:
+		ety, defaults, err := CtyTypeOf(exp.TypeExpression)
+		if err != nil {
+			return cty.NilType, nil, err
+		}
+		ty := cty.Map(ety)
+		return ty, collectionDefaults(ty, defaults), nil
+	case *ast.ObjectTypeLiteral:
+		return ctyObjectType(exp)
+	case nil:
+		return cty.NilType, nil, fmt.Errorf("missing type expression")
+	}
+
+	return cty.NilType, nil, fmt.Errorf("%s is not a supported type expression", exp.String())
+}
+
+func ctyObjectType(exp *ast.ObjectTypeLiteral) (cty.Type, *typeexpr.Defaults, error) {
+	spec, ok := exp.ObjectSpec.(*ast.ObjectLiteral)
+	if !ok {
+		return cty.NilType, nil, fmt.Errorf("object() requires a map of attribute names to types")
+	}
+
+	attrTypes := make(map[string]cty.Type)
+	defaultValues := make(map[string]cty.Value)
+	children := make(map[string]*typeexpr.Defaults)
+	var optional []string
+
+	for k, v := range spec.KVPairs {
+		name := k.String()
+
+		var defaultValue ast.Expression
+		if opt, ok := v.(*ast.OptionalTypeLiteral); ok {
+			optional = append(optional, name)
+			defaultValue = opt.DefaultValue
+			v = opt.TypeExpression
+		}
+
+		aty, defaults, err := CtyTypeOf(v)
+		if err != nil {
+			return cty.NilType, nil, fmt.Errorf("%s: %v", name, err)
+		}
+		attrTypes[name] = aty
+		if defaults != nil {
+			children[name] = defaults
+		}
+
+		if defaultValue != nil {
+			val, err := CtyValue(defaultValue)
+			if err != nil {
+				return cty.NilType, nil, fmt.Errorf("%s: %v", name, err)
+			}
+
+			val, err = convert.Convert(val, aty)
+			if err != nil {
+				return cty.NilType, nil, fmt.Errorf("%s: default value is not compatible with the attribute's type: %v", name, err)
+			}
+			defaultValues[name] = val
+		}
+	}
+
+	ty := cty.ObjectWithOptionalAttrs(attrTypes, optional)
+	if len(defaultValues) == 0 && len(children) == 0 {
+		return ty, nil, nil
+	}
+
+	defaults := &typeexpr.Defaults{Type: ty}
+	if len(defaultValues) > 0 {
+		defaults.DefaultValues = defaultValues
+	}
+	if len(children) > 0 {
+		defaults.Children = children
+	}
+
+	return ty, defaults, nil
+}
+
+func collectionDefaults(ty cty.Type, defaults *typeexpr.Defaults) *typeexpr.Defaults {
+	if defaults == nil {
+		return nil
+	}
+	return &typeexpr.Defaults{
+		Type:     ty,
+		Children: map[string]*typeexpr.Defaults{"": defaults},
+	}
+}
+
+// CtyValue converts a literal value expression, such as the default value of
+// an optional attribute, into a cty value. Lists become tuples and objects
+// become objects, as they would when HCL evaluates the same literal.
+func CtyValue(exp ast.Expression) (cty.Value, error) {
+	switch exp := exp.(type) {
+	case *ast.NullLiteral:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case *ast.Bool:
+		return cty.BoolVal(exp.Value), nil
+	case *ast.NumberLiteral:
+		return cty.NumberVal(exp.Value), nil
+	case *ast.StringLiteral:
+		return cty.StringVal(exp.Value), nil
+	case *ast.ListLiteral:
+		elems := make([]cty.Value, 0, len(exp.Elements))
+		for _, el := range exp.Elements {
+			val, err := CtyValue(el)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems = append(elems, val)
+		}
+		return cty.TupleVal(elems), nil
+	case *ast.ObjectLiteral:
+		attrs := make(map[string]cty.Value, len(exp.KVPairs))
+		for k, v := range exp.KVPairs {
+			var name string
+			switch k := k.(type) {
+			case *ast.Identifier:
+				name = k.Value
+			case *ast.StringLiteral:
+				name = k.Value
+			default:
+				return cty.NilVal, fmt.Errorf("object keys must be identifiers or strings, got %s", k.String())
+			}
+
+			val, err := CtyValue(v)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[name] = val
+		}
+		return cty.ObjectVal(attrs), nil
+	case nil:
+		return cty.NilVal, fmt.Errorf("missing value")
+	}
+
+	return cty.NilVal, fmt.Errorf("%s is not a literal value", exp.String())
+}
+
+// TypeFromCty converts a cty type into a type expression. When defaults is
+// non-nil, optional object attributes with a default value are rendered as
+// optional(type, default).
+func TypeFromCty(ty cty.Type, defaults *typeexpr.Defaults) (*ast.Type, error) {
+	exp, err := TypeExpressionFromCty(ty, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	return newType(exp), nil
+}
+
+func newType(exp ast.Expression) *ast.Type {
+	// Type expressions always start with their keyword
+	tok := keyword(token.LookupIdent(exp.TokenLiteral()), exp.TokenLiteral())
+
+	return &ast.Type{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{Token: tok, Expression: exp},
+		},
+	}
+}
+
+// InferType returns the type of a literal value, for a variable that is
+// declared without a type but with the value as its default. A list becomes
+// list(T) and an object map(T) when all of their elements have the same type
+// T, while an object with differently typed attributes becomes an object
+// type. Null, empty collections and lists of mixed types are typed any.
+func InferType(value ast.Expression) *ast.Type {
+	return newType(inferTypeExpression(value))
+}
+
+func inferTypeExpression(value ast.Expression) ast.Expression {
+	switch value := value.(type) {
+	case *ast.Bool:
+		return &ast.BoolTypeLiteral{Token: keyword(token.BOOL_TYPE, "bool")}
+	case *ast.NumberLiteral:
+		return &ast.NumberTypeLiteral{Token: keyword(token.NUMBER_TYPE, "number")}
+	case *ast.StringLiteral:
+		return &ast.StringTypeLiteral{Token: keyword(token.STRING_TYPE, "string")}
+	case *ast.ListLiteral:
+		elementTypes := make([]ast.Expression, 0, len(value.Elements))
+		for _, el := range value.Elements {
+			elementTypes = append(elementTypes, inferTypeExpression(el))
+		}
+		return &ast.ListTypeLiteral{Token: keyword(token.LIST_TYPE, "list"), TypeExpression: unifyTypes(elementTypes)}
+	case *ast.ObjectLiteral:
+		pairs := value.SortedKVPairs()
+
+		attributeTypes := make([]ast.Expression, 0, len(pairs))
+		for _, kv := range pairs {
+			attributeTypes = append(attributeTypes, inferTypeExpression(kv.Value))
+		}
+
+		ety := unifyTypes(attributeTypes)
+		if _, ok := ety.(*ast.AnyTypeLiteral); !ok || len(pairs) == 0 {
+			return &ast.MapTypeLiteral{Token: keyword(token.MAP_TYPE, "map"), TypeExpression: ety}
+		}
+
+		spec := &ast.ObjectLiteral{
+			Token:   keyword(token.LEFT_CURLY_BRACE, "{"),
+			KVPairs: make(map[ast.Expression]ast.Expression),
+		}
+		for i, kv := range pairs {
+			name := ast.KeyName(kv.Key)
+			spec.KVPairs[&ast.Identifier{Token: keyword(token.IDENT, name), Value: name}] = attributeTypes[i]
+		}
+		return &ast.ObjectTypeLiteral{Token: keyword(token.OBJECT_TYPE, "object"), ObjectSpec: spec}
+	}
+
+	return &ast.AnyTypeLiteral{Token: keyword(token.ANY_TYPE, "any")}
+}
+
+// unifyTypes returns the type shared by all of the given types, ignoring the
+// any of null values, or any if they differ.
+func unifyTypes(types []ast.Expression) ast.Expression {
+	var unified ast.Expression
+	for _, ty := range types {
+		if _, ok := ty.(*ast.AnyTypeLiteral); ok {
+			continue
+		}
+		if unified == nil {
+			unified = ty
+		} else if unified.String() != ty.String() {
+			return &ast.AnyTypeLiteral{Token: keyword(token.ANY_TYPE, "any")}
+		}
+	}
+
+	if unified == nil {
+		return &ast.AnyTypeLiteral{Token: keyword(token.ANY_TYPE, "any")}
+	}
+	return unified
+}
+
+// TypeExpressionFromCty converts a cty type into a single type expression.
+func TypeExpressionFromCty(ty cty.Type, defaults *typeexpr.Defaults) (ast.Expression, error) {
+	switch {
+	case ty == cty.DynamicPseudoType:
+		return &ast.AnyTypeLiteral{Token: keyword(token.ANY_TYPE, "any")}, nil
+	case ty == cty.Bool:
+		return &ast.BoolTypeLiteral{Token: keyword(token.BOOL_TYPE, "bool")}, nil
+	case ty == cty.Number:
+		return &ast.NumberTypeLiteral{Token: keyword(token.NUMBER_TYPE, "number")}, nil
+	case ty == cty.String:
+		return &ast.StringTypeLiteral{Token: keyword(token.STRING_TYPE, "string")}, nil
+	case ty.IsListType():
+		ety, err := TypeExpressionFromCty(ty.ElementType(), childDefaults(defaults, ""))
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ListTypeLiteral{Token: keyword(token.LIST_TYPE, "list"), TypeExpression: ety}, nil
+	case ty.IsMapType():
+		ety, err := TypeExpressionFromCty(ty.ElementType(), childDefaults(defaults, ""))
+		if err != nil {
+			return nil, err
+		}
+		return &ast.MapTypeLiteral{Token: keyword(token.MAP_TYPE, "map"), TypeExpression: ety}, nil
+	case ty.IsObjectType():
+		spec := &ast.ObjectLiteral{
+			Token:   keyword(token.LEFT_CURLY_BRACE, "{"),
+			KVPairs: make(map[ast.Expression]ast.Expression),
+		}
+
+		for name, aty := range ty.AttributeTypes() {
+			exp, err := TypeExpressionFromCty(aty, childDefaults(defaults, name))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", name, err)
+			}
+
+			if ty.AttributeOptional(name) {
+				opt := &ast.OptionalTypeLiteral{Token: keyword(token.OPTIONAL_TYPE, "optional"), TypeExpression: exp}
+				if defaults != nil {
+					if val, ok := defaults.DefaultValues[name]; ok {
+						opt.DefaultValue, err = ValueFromCty(val)
+						if err != nil {
+							return nil, fmt.Errorf("%s: %v", name, err)
+						}
+					}
+				}
+				exp = opt
+			}
+
+			spec.KVPairs[&ast.Identifier{Token: keyword(token.IDENT, name), Value: name}] = exp
+		}
+
+		return &ast.ObjectTypeLiteral{Token: keyword(token.OBJECT_TYPE, "object"), ObjectSpec: spec}, nil
+	}
+
+	return nil, fmt.Errorf("%s types are not supported", ty.FriendlyName())
+}
+
+func childDefaults(defaults *typeexpr.Defaults, key string) *typeexpr.Defaults {
+	if defaults == nil {
+		return nil
+	}
+	return defaults.Children[key]
+}
+
+// ValueFromCty converts a known cty value into a literal value expression.
+func ValueFromCty(val cty.Value) (ast.Expression, error) {
+	if !val.IsKnown() {
+		return nil, fmt.Errorf("cannot convert an unknown value")
+	}
+	if val.IsNull() {
+		return &ast.NullLiteral{Token: keyword(token.NULL, "null")}, nil
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.Bool:
+		if val.True() {
+			return &ast.Bool{Token: keyword(token.TRUE, "true"), Value: true}, nil
+		}
+		return &ast.Bool{Token: keyword(token.FALSE, "false"), Value: false}, nil
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		return &ast.NumberLiteral{Token: keyword(token.NUMBER, bf.Text('f', -1)), Value: bf}, nil
+	case ty == cty.String:
+		s := val.AsString()
+		return &ast.StringLiteral{Token: keyword(token.STRING, s), Value: s}, nil
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		list := &ast.ListLiteral{Token: keyword(token.LEFT_SQUARE_BRACE, "["), Elements: []ast.Expression{}}
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			el, err := ValueFromCty(v)
+			if err != nil {
+				return nil, err
+			}
+			list.Elements = append(list.Elements, el)
+		}
+		return list, nil
+	case ty.IsMapType(), ty.IsObjectType():
+		obj := &ast.ObjectLiteral{
+			Token:   keyword(token.LEFT_CURLY_BRACE, "{"),
+			KVPairs: make(map[ast.Expression]ast.Expression),
+		}
+
+		var keys []string
+		values := make(map[string]cty.Value)
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			keys = append(keys, k.AsString())
+			values[k.AsString()] = v
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			v, err := ValueFromCty(values[k])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", k, err)
+			}
+
+			var key ast.Expression = &ast.StringLiteral{Token: keyword(token.STRING, k), Value: k}
+			if identifierPattern.MatchString(k) {
+				key = &ast.Identifier{Token: keyword(token.IDENT, k), Value: k}
+			}
+			obj.KVPairs[key] = v
+		}
+		return obj, nil
+	}
+
+	return nil, fmt.Errorf("%s values are not supported", ty.FriendlyName())
+}
+
+func keyword(tokenType token.TokenType, literal string) token.Token {
+	return token.Token{Type: tokenType, Literal: literal}
+}
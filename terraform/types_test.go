@@ -0,0 +1,182 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform/ast"
+	"github.com/lolabyte/tf2go/terraform/lexer"
+	"github.com/lolabyte/tf2go/terraform/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+func parseType(t *testing.T, input string) *ast.Type {
+	p := parser.New(lexer.New(input))
+	typeDef := p.ParseType()
+	if len(p.Errors()) > 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return typeDef
+}
+
+func TestCtyType(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected cty.Type
+	}{
+		{"any", cty.DynamicPseudoType},
+		{"bool", cty.Bool},
+		{"number", cty.Number},
+		{"string", cty.String},
+		{"list(string)", cty.List(cty.String)},
+		{"map(list(number))", cty.Map(cty.List(cty.Number))},
+		{
+			`object({ name = string, port = optional(number) })`,
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name": cty.String,
+				"port": cty.Number,
+			}, []string{"port"}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			ty, defaults, err := CtyType(parseType(t, tc.input))
+			assert.NoError(t, err)
+			assert.Nil(t, defaults)
+			assert.True(t, tc.expected.Equals(ty), "expected %#v, got %#v", tc.expected, ty)
+		})
+	}
+}
+
+func TestCtyTypeDefaults(t *testing.T) {
+	typeDef := parseType(t, `list(object({
+		name    = string
+		enabled = optional(bool, true)
+		ports   = optional(list(number), [80, 443])
+		website = optional(object({
+			index_document = optional(string, "index.html")
+		}), {})
+	}))`)
+
+	ty, defaults, err := CtyType(typeDef)
+	assert.NoError(t, err)
+	assert.True(t, ty.IsListType())
+
+	objDefaults := defaults.Children[""]
+	assert.True(t, objDefaults.DefaultValues["enabled"].RawEquals(cty.True))
+	assert.True(t, objDefaults.DefaultValues["ports"].RawEquals(
+		cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+	))
+	assert.True(t, objDefaults.Children["website"].DefaultValues["index_document"].RawEquals(cty.StringVal("index.html")))
+
+	// Defaults are applied the same way as HCL's
+	val := defaults.Apply(cty.TupleVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("a")}),
+	}))
+	obj := val.Index(cty.NumberIntVal(0))
+	assert.True(t, obj.GetAttr("enabled").RawEquals(cty.True))
+	assert.True(t, obj.GetAttr("website").GetAttr("index_document").RawEquals(cty.StringVal("index.html")))
+}
+
+func TestCtyTypeInvalidDefault(t *testing.T) {
+	_, _, err := CtyType(parseType(t, `object({ port = optional(number, "http") })`))
+	assert.Error(t, err)
+}
+
+func TestTypeFromCty(t *testing.T) {
+	inputs := []string{
+		"any",
+		"list(string)",
+		"map(list(number))",
+		`object({ name = string, port = optional(number, 8080), tags = optional(map(string)) })`,
+		`list(object({ website = optional(object({ index = optional(string, "index.html") }), {}) }))`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			ty, defaults, err := CtyType(parseType(t, input))
+			assert.NoError(t, err)
+
+			typeDef, err := TypeFromCty(ty, defaults)
+			assert.NoError(t, err)
+
+			roundTripped, roundTrippedDefaults, err := CtyType(typeDef)
+			assert.NoError(t, err)
+			assert.True(t, ty.Equals(roundTripped), "expected %#v, got %#v", ty, roundTripped)
+			assert.Equal(t, defaults == nil, roundTrippedDefaults == nil)
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := TypeFromCty(cty.Set(cty.String), nil)
+		assert.EqualError(t, err, "set of string types are not supported")
+	})
+}
+
+func TestValueFromCty(t *testing.T) {
+	val := cty.ObjectVal(map[string]cty.Value{
+		"name":    cty.StringVal("web"),
+		"ports":   cty.ListVal([]cty.Value{cty.NumberIntVal(80)}),
+		"enabled": cty.True,
+		"owner":   cty.NullVal(cty.String),
+		"my key":  cty.StringVal("quoted"),
+	})
+
+	exp, err := ValueFromCty(val)
+	assert.NoError(t, err)
+
+	roundTripped, err := CtyValue(exp)
+	assert.NoError(t, err)
+	assert.True(t, roundTripped.GetAttr("name").RawEquals(cty.StringVal("web")))
+	assert.True(t, roundTripped.GetAttr("enabled").RawEquals(cty.True))
+	assert.True(t, roundTripped.GetAttr("owner").IsNull())
+	assert.True(t, roundTripped.GetAttr("my key").RawEquals(cty.StringVal("quoted")))
+	assert.True(t, roundTripped.GetAttr("ports").RawEquals(cty.TupleVal([]cty.Value{cty.NumberIntVal(80)})))
+
+	exp, err = ValueFromCty(cty.NumberFloatVal(-1.5))
+	assert.NoError(t, err)
+	assert.Equal(t, "-1.5", exp.String())
+}
+
+func TestInferType(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{`"web"`, "string"},
+		{"-1.5", "number"},
+		{"true", "bool"},
+		{"null", "any"},
+		{"[80, 443]", "list(number)"},
+		{`[null, "a"]`, "list(string)"},
+		{`[1, "a"]`, "list(any)"},
+		{"[]", "list(any)"},
+		{"{}", "map(any)"},
+		{`{ env = "dev", "app.kubernetes.io/name" = "web" }`, "map(string)"},
+		{`{ a = [1], b = [2, 3] }`, "map(list(number))"},
+		{`{ name = "web", replicas = 3, zones = ["a"] }`, "object({name = string, replicas = number, zones = list(string)})"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			p := parser.New(lexer.New(tc.value))
+			value := p.ParseValue()
+			if len(p.Errors()) > 0 {
+				t.Fatalf("parser errors: %v", p.Errors())
+			}
+
+			typeDef := InferType(value)
+			assert.Equal(t, tc.expected, typeDef.String())
+
+			// The inferred type accepts the value it was inferred from
+			ty, _, err := CtyType(typeDef)
+			assert.NoError(t, err)
+			val, err := CtyValue(value)
+			assert.NoError(t, err)
+			_, err = convert.Convert(val, ty)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -94,5 +94,9 @@ variable "container" {
 
 
 variable "optional_list" {
-  type = optional(list(number), [])
+  type = object(
+    {
+      values = optional(list(number), [])
+    }
+  )
 }
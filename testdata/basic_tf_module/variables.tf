@@ -34,6 +34,19 @@ variable "number_with_default" {
   default = 99
 }
 
+variable "ratio" {
+  type    = number
+  default = 0.5
+}
+
+variable "scaling" {
+  type = object({
+    factor = optional(number, 1.5)
+    steps  = optional(number, 2)
+  })
+  default = {}
+}
+
 variable "list_of_bool" {
   type = list(bool)
 }
@@ -94,5 +107,97 @@ variable "container" {
 
 
 variable "optional_list" {
-  type = optional(list(number), [])
+  type = object(
+    {
+      values = optional(list(number), [])
+    }
+  )
+}
+
+variable "server" {
+  type = object({
+    name    = string
+    ports   = list(number)
+    labels  = map(string)
+    weight  = optional(number)
+    enabled = optional(bool)
+    extra   = optional(any)
+  })
+  default = {
+    name    = "web"
+    ports   = [80, 443]
+    labels  = { "app.kubernetes.io/name" = "web" }
+    weight  = -1
+    enabled = true
+    extra   = { ratio = 0.5, tags = ["a", null] }
+  }
+}
+
+variable "script" {
+  type    = string
+  default = <<-EOT
+    #!/bin/sh
+    echo "hello"
+  EOT
+}
+
+variable "untyped" {
+}
+
+variable "untyped_ports" {
+  default = [80, 443]
+}
+
+variable "untyped_ratio" {
+  default = 0.25
+}
+
+variable "untyped_tags" {
+  default = {
+    env  = "dev"
+    team = "platform"
+  }
+}
+
+variable "untyped_settings" {
+  default = {
+    name     = "web"
+    replicas = 3
+    zones    = ["a", "b"]
+  }
+}
+
+variable "annotations" {
+  type = map(any)
+  default = {
+    replicas = 3
+  }
+}
+
+variable "metadata" {
+  type     = any
+  default  = {}
+  nullable = false
+}
+
+variable "region" {
+  type     = string
+  default  = "eu-west-1"
+  nullable = false
+
+  validation {
+    condition     = contains(["eu-west-1", "us-east-1"], var.region)
+    error_message = "The region must be eu-west-1 or us-east-1."
+  }
+}
+
+variable "environment" {
+  type        = string
+  description = "The environment to deploy to"
+  default     = "dev"
+
+  validation {
+    condition     = var.environment == "dev" || (var.environment == "staging" || var.environment == "prod")
+    error_message = "The environment must be dev, staging or prod."
+  }
 }
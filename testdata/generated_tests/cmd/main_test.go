@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	err := run(context.Background(), "bogus", nil)
+	assert.EqualError(t, err, "unknown command \"bogus\"\n\n"+usage)
+
+	err = run(context.Background(), "plan", []string{"-working-dir", t.TempDir(), "-bool", "-number=3"})
+	assert.EqualError(t, err, "missing required flags -container, -list_of_bool, -list_of_number, -list_of_string, -local_file_path, -optional_list, -sensitive_string, -string, -things, -untyped")
+}
+
+func TestVariableFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test_module plan", flag.ContinueOnError)
+	v := variableFlags(fs)
+	err := fs.Parse([]string{
+		"-number=3",
+		"-string", "a b",
+		"-list_of_string", `["a", "b"]`,
+		"-container", `{foo = "f", bar = {baz = "b", qux = [{bing = "x", bong = 1}]}}`,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, int64(3), v.Number)
+	assert.Equal(t, "a b", v.String)
+	assert.Equal(t, []string{"a", "b"}, v.ListOfString)
+	assert.Equal(t, "f", v.Container.Foo)
+	assert.Equal(t, "x", v.Container.Bar.Qux[0].Bing)
+	assert.Equal(t, "dev", v.Environment)
+
+	err = fs.Parse([]string{"-list_of_number", `["a"]`})
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package test_module
+
+import (
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeepCopy(t *testing.T) {
+	v := requiredVariables()
+	c := v.DeepCopy()
+	assert.True(t, v.Equal(c))
+
+	c.Container.Bar.Qux[0].Bong = 2
+	c.Server.Labels["env"] = "prod"
+	c.ListOfNumber[0] = 2
+	assert.Equal(t, int64(1), v.Container.Bar.Qux[0].Bong)
+	assert.NotContains(t, v.Server.Labels, "env")
+	assert.Equal(t, []int64{1}, v.ListOfNumber)
+	assert.False(t, v.Equal(c))
+}
+
+func TestDiff(t *testing.T) {
+	v := requiredVariables()
+	other := v.DeepCopy()
+	other.Container.Bar.Qux[0].Bong = 2
+	other.Server.Labels["env"] = "prod"
+	other.SensitiveString = "other secret"
+	assert.NoError(t, other.SetNull("script"))
+
+	assert.Equal(t, []terraform.Change{
+		{Path: "container.bar.qux[0].bong", Old: int64(1), New: int64(2)},
+		{Path: "script", Old: v.Script},
+		{Path: "sensitive_string", Sensitive: true},
+		{Path: `server.labels["env"]`, New: "prod"},
+	}, v.Diff(other))
+	assert.Empty(t, v.Diff(v.DeepCopy()))
+}
@@ -0,0 +1,30 @@
+package test_module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVariables(t *testing.T) {
+	expected := requiredVariables()
+	v := NewVariables(RequiredVariables{
+		Bool:            expected.Bool,
+		Container:       expected.Container,
+		ListOfBool:      expected.ListOfBool,
+		ListOfNumber:    expected.ListOfNumber,
+		ListOfString:    expected.ListOfString,
+		LocalFilePath:   expected.LocalFilePath,
+		Number:          expected.Number,
+		OptionalList:    expected.OptionalList,
+		SensitiveString: expected.SensitiveString,
+		String:          expected.String,
+		Things:          expected.Things,
+		Untyped:         expected.Untyped,
+	}, WithRegion("us-east-1"), WithNumberWithDefault(7))
+
+	expected.Region = "us-east-1"
+	expected.NumberWithDefault = 7
+	assert.Equal(t, expected, v)
+	assert.Equal(t, "dev", v.Environment)
+}
@@ -0,0 +1,57 @@
+package test_module
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeVariables(t *testing.T) {
+	t.Run("decodes whole numbers however they are written", func(t *testing.T) {
+		b, err := json.Marshal(requiredVariables())
+		assert.NoError(t, err)
+
+		var values map[string]json.RawMessage
+		assert.NoError(t, json.Unmarshal(b, &values))
+		values["number"] = json.RawMessage("1e3")
+		values["number_with_default"] = json.RawMessage("7.0")
+		b, err = json.Marshal(values)
+		assert.NoError(t, err)
+
+		v, err := DecodeVariables(bytes.NewReader(b))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), v.Number)
+		assert.Equal(t, int64(7), v.NumberWithDefault)
+	})
+
+	t.Run("reports every invalid value and missing required variable", func(t *testing.T) {
+		_, err := DecodeVariables(strings.NewReader(`{
+			"number": 1.5,
+			"ratio": "half",
+			"container": {"foo": "a", "bar": {"baz": "b", "qux": [{"bing": "c", "bong": 2.5}]}}
+		}`))
+
+		errs, ok := err.(terraform.DecodeErrors)
+		if assert.True(t, ok, "%v", err) {
+			assert.Equal(t, terraform.DecodeErrors{
+				{Path: "bool", Reason: "required variable is missing"},
+				{Path: "container.bar.qux[0].bong", Reason: "2.5 is not a whole number"},
+				{Path: "list_of_bool", Reason: "required variable is missing"},
+				{Path: "list_of_number", Reason: "required variable is missing"},
+				{Path: "list_of_string", Reason: "required variable is missing"},
+				{Path: "local_file_path", Reason: "required variable is missing"},
+				{Path: "number", Reason: "1.5 is not a whole number"},
+				{Path: "optional_list", Reason: "required variable is missing"},
+				{Path: "ratio", Reason: "expected a number, got a string"},
+				{Path: "sensitive_string", Reason: "required variable is missing"},
+				{Path: "string", Reason: "required variable is missing"},
+				{Path: "things", Reason: "required variable is missing"},
+				{Path: "untyped", Reason: "required variable is missing"},
+			}, errs)
+		}
+	})
+}
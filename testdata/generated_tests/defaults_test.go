@@ -0,0 +1,28 @@
+package test_module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultVariables(t *testing.T) {
+	v := DefaultVariables()
+	assert.Equal(t, []*bool{ptr(false), ptr(true), ptr(false)}, v.ListOfBoolWithDefault)
+	assert.Equal(t, []int64{98, 99, 100}, v.ListOfNumberWithDefault)
+	assert.Equal(t, "#!/bin/sh\necho \"hello\"\n", v.Script)
+	assert.Equal(t, 0.5, v.Ratio)
+	assert.Equal(t, &Scaling{Factor: ptr(1.5), Steps: ptr[int64](2)}, v.Scaling)
+
+	assert.Equal(t, "web", v.Server.Name)
+	assert.Equal(t, []int64{80, 443}, v.Server.Ports)
+	assert.Equal(t, map[string]string{"app.kubernetes.io/name": "web"}, v.Server.Labels)
+	assert.Equal(t, ptr[int64](-1), v.Server.Weight)
+	assert.Equal(t, ptr(true), v.Server.Enabled)
+
+	// The types of untyped variables are inferred from their defaults
+	assert.Equal(t, []int64{80, 443}, v.UntypedPorts)
+	assert.Equal(t, 0.25, v.UntypedRatio)
+	assert.Equal(t, map[string]string{"env": "dev", "team": "platform"}, v.UntypedTags)
+	assert.Equal(t, &UntypedSettings{Name: "web", Replicas: 3, Zones: []string{"a", "b"}}, v.UntypedSettings)
+}
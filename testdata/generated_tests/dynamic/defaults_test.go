@@ -0,0 +1,35 @@
+package test_module
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultAnyValues(t *testing.T) {
+	v := DefaultVariables()
+	assert.Equal(t, map[string]terraform.Dynamic{"replicas": terraform.Dynamic("3")}, v.Annotations)
+	assert.Equal(t, terraform.Dynamic("{}"), v.Metadata)
+	assert.JSONEq(t, `{"ratio": 0.5, "tags": ["a", null]}`, v.Server.Extra.String())
+
+	tags, err := v.Server.Extra.AsMap()
+	assert.NoError(t, err)
+	list, err := tags["tags"].AsList()
+	assert.NoError(t, err)
+	assert.True(t, list[1].IsNull())
+}
+
+func TestDynamicRoundTrip(t *testing.T) {
+	v := requiredVariables()
+	v.Untyped = terraform.Dynamic(`{"nested": [1, {"a": null}]}`)
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	decoded, err := DecodeVariables(bytes.NewReader(b))
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(v.Untyped), string(decoded.Untyped))
+	assert.True(t, v.Equal(decoded))
+}
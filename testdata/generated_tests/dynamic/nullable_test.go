@@ -0,0 +1,18 @@
+package test_module
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lolabyte/tf2go/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicNull(t *testing.T) {
+	v := requiredVariables()
+	v.Metadata = terraform.Dynamic("null")
+	assert.EqualError(t, v.Validate(), `variable "metadata" is not nullable`)
+
+	_, err := json.Marshal(v)
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package test_module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultAnyValues(t *testing.T) {
+	// Numbers of the any type are float64, as encoding/json decodes them
+	v := DefaultVariables()
+	assert.Equal(t, map[string]interface{}{"replicas": 3.0}, v.Annotations)
+	assert.Equal(t, map[string]interface{}{}, v.Metadata)
+	assert.Equal(t, map[string]interface{}{"ratio": 0.5, "tags": []interface{}{"a", nil}}, v.Server.Extra)
+}
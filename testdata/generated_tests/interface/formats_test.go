@@ -0,0 +1,47 @@
+package test_module
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAML(t *testing.T) {
+	v := requiredVariables()
+	v.Number = 0
+	assert.NoError(t, v.SetNull("script"))
+
+	path := filepath.Join(t.TempDir(), "variables.yaml")
+	assert.NoError(t, v.SaveYAML(path))
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "\nnumber: 0\n")
+	assert.Contains(t, string(b), "\nscript: null\n")
+
+	loaded, err := LoadVariablesYAML(path)
+	assert.NoError(t, err)
+	assert.True(t, v.Equal(loaded))
+	assert.Equal(t, []string{"script"}, loaded.NullVariables())
+
+	b = bytes.Replace(b, []byte("\nnumber: 0\n"), []byte("\nnumber: 1.5\n"), 1)
+	assert.NoError(t, os.WriteFile(path, b, 0o644))
+	_, err = LoadVariablesYAML(path)
+	assert.EqualError(t, err, path+": number: 1.5 is not a whole number")
+}
+
+func TestTOML(t *testing.T) {
+	v := requiredVariables()
+	path := filepath.Join(t.TempDir(), "variables.toml")
+	assert.EqualError(t, v.SaveTOML(path), `cannot write server.extra.tags[1] to TOML, which has no null`)
+
+	v.Server.Extra = map[string]interface{}{"ratio": 0.5}
+	assert.NoError(t, v.SaveTOML(path))
+
+	loaded, err := LoadVariablesTOML(path)
+	assert.NoError(t, err)
+	assert.True(t, v.Equal(loaded))
+}
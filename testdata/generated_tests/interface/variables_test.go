@@ -0,0 +1,20 @@
+package test_module
+
+// requiredVariables returns the default variables with every required
+// variable set.
+func requiredVariables() Variables {
+	v := DefaultVariables()
+	v.Bool = ptr(true)
+	v.Container = &Container{Foo: "foo", Bar: &Bar{Baz: "baz", Qux: []*Qux{{Bing: "bing", Bong: 1}}}}
+	v.ListOfBool = []*bool{ptr(true)}
+	v.ListOfNumber = []int64{1}
+	v.ListOfString = []string{"a"}
+	v.LocalFilePath = "out.txt"
+	v.Number = 1
+	v.OptionalList = &OptionalList{Values: []int64{1}}
+	v.SensitiveString = "secret"
+	v.String = "string"
+	v.Things = []*Things{{Foo: []int64{1}}}
+	v.Untyped = "untyped"
+	return v
+}
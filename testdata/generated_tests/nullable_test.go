@@ -0,0 +1,41 @@
+package test_module
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetNull(t *testing.T) {
+	var v Variables
+	assert.NoError(t, v.SetNull("script"))
+	assert.EqualError(t, v.SetNull("region"), `variable "region" is not nullable`)
+	assert.EqualError(t, v.SetNull("regoin"), `unknown variable "regoin"`)
+	assert.Equal(t, []string{"script"}, v.NullVariables())
+}
+
+func TestMarshalJSONNulls(t *testing.T) {
+	v := requiredVariables()
+	assert.NoError(t, v.SetNull("script"))
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+
+	var values map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(b, &values))
+	assert.Equal(t, "null", string(values["script"]))
+	assert.Equal(t, `"eu-west-1"`, string(values["region"]))
+
+	decoded, err := DecodeVariables(bytes.NewReader(b))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"script"}, decoded.NullVariables())
+	assert.True(t, v.Equal(decoded))
+
+	values["region"] = json.RawMessage("null")
+	b, err = json.Marshal(values)
+	assert.NoError(t, err)
+	_, err = DecodeVariables(bytes.NewReader(b))
+	assert.EqualError(t, err, `variable "region" is not nullable`)
+}
@@ -0,0 +1,49 @@
+package test_module
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zeroVariables returns variables whose required values are all the zero
+// values of their types, which must still be written to tfvars files.
+func zeroVariables() Variables {
+	v := requiredVariables()
+	v.Bool = ptr(false)
+	v.Container = &Container{Bar: &Bar{Qux: []*Qux{{}}}}
+	v.ListOfNumber = []int64{}
+	v.LocalFilePath = ""
+	v.Number = 0
+	v.OptionalList = &OptionalList{Values: []int64{}}
+	v.String = ""
+	v.Things = []*Things{{Foo: []int64{}}}
+	return v
+}
+
+func TestWriteTFVars(t *testing.T) {
+	v := zeroVariables()
+	path, err := v.WriteTFVars(t.TempDir())
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	for _, expected := range []string{"number ", "string ", "local_file_path ", "bool "} {
+		assert.Contains(t, string(b), "\n"+expected)
+	}
+
+	loaded, err := LoadTFVars(path)
+	assert.NoError(t, err)
+	assert.Equal(t, v, loaded)
+}
+
+func TestWriteTFVarJSON(t *testing.T) {
+	v := zeroVariables()
+	path, err := v.WriteTFVarJSON(t.TempDir())
+	assert.NoError(t, err)
+
+	loaded, err := LoadTFVars(path)
+	assert.NoError(t, err)
+	assert.Equal(t, v, loaded)
+}
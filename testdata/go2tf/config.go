@@ -0,0 +1,102 @@
+// Package config holds Go types that tf2go go2tf generates Terraform
+// variables from.
+package config
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Config is the configuration of a service.
+type Config struct {
+	// Region is the region to deploy
+	// the service to.
+	Region string `json:"region"`
+
+	Replicas int
+	Mode     Mode              `json:"mode"`
+	Enabled  *bool             `json:"enabled,omitempty"`
+	Zone     string            `json:"zone,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Servers  []Server          `json:"servers"`
+	Extra    json.RawMessage   `json:"extra"`
+	Password string            `json:"password" tf2go:"password,required,sensitive"`
+	Skipped  string            `json:"-"`
+
+	Common
+	internal string
+}
+
+// Server is a server of the service.
+type Server struct {
+	Name      string        `json:"name"`
+	Ports     []uint16      `json:"ports"`
+	Weight    *float64      `json:"weight,omitempty"`
+	Timeout   time.Duration `json:"timeout"`
+	CreatedAt time.Time     `json:"created_at"`
+	Cert      []byte        `json:"cert"`
+	Enabled   *bool         `json:"enabled" tf2go:"enabled,required"`
+	Tags      []string      `json:"tags,omitempty"`
+}
+
+// Common holds the settings shared by every service.
+type Common struct {
+	// Environment is the environment to deploy to.
+	Environment string `json:"environment"`
+}
+
+// Tree has no Terraform type, as it is recursive.
+type Tree struct {
+	Children []Tree `json:"children"`
+}
+
+// Reserved has a field named like a meta-argument of module blocks.
+type Reserved struct {
+	Count int `json:"count"`
+}
+
+// Mode is how a service is deployed.
+type Mode string
+
+// Listed has a doc comment with a list of its own.
+type Listed struct {
+	// Steps are the steps to run:
+	//
+	//   - build
+	//   - deploy
+	Steps []string `json:"steps"`
+
+	// Timeout holds the timeout variable.
+	//
+	//   - Terraform type: number
+	//   - Default: 30
+	Timeout *int64 `json:"timeout,omitempty"`
+}
+
+// Conflicting embeds structs with fields of the same name, which
+// encoding/json decides between like Go does, or encodes neither.
+type Conflicting struct {
+	Name string `json:"name"`
+
+	First
+	Second
+	Tagged
+}
+
+// First is embedded in Conflicting.
+type First struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+	Team  string
+}
+
+// Second is embedded in Conflicting.
+type Second struct {
+	Owner string `json:"owner"`
+	Team  string
+}
+
+// Tagged is embedded in Conflicting.
+type Tagged struct {
+	Team string `json:"Team"`
+}
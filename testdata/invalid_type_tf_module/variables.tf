@@ -0,0 +1,10 @@
+variable "optional_list" {
+  type = optional(list(number), [])
+}
+
+variable "broken" {
+  type = object({
+    a = foo(string)
+    b = list(string, number)
+  })
+}